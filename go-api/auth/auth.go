@@ -0,0 +1,61 @@
+// Package auth 管理APIのBearerトークン認証
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/smilemasa/go-api/model"
+)
+
+type actorKey struct{}
+
+// Actor 認証したリクエストの操作者（トークンに対応する利用者ID）を返す
+// 認証しない設定の場合は ok が false になる
+func Actor(ctx context.Context) (actor string, ok bool) {
+	actor, ok = ctx.Value(actorKey{}).(string)
+	return actor, ok
+}
+
+// Middleware Authorization ヘッダーの Bearer トークンが credentials（トークンから利用者IDへのマップ）の
+// いずれとも一致しないリクエストを401で拒否し、一致した場合は利用者IDを操作者としてコンテキストに設定する
+// credentials が空の場合は認証しない。publicPrefixes で始まるパス（ヘルスチェックや画像配信）は常に許可する
+func Middleware(credentials map[string]string, publicPrefixes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(credentials) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range publicPrefixes {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			actor, found := "", false
+			if ok {
+				// 一致したトークンによって処理時間が変わらないよう、すべてのトークンと比較する
+				for token, user := range credentials {
+					if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+						actor, found = user, true
+					}
+				}
+			}
+			if !found {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="cookorder"`)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(model.ErrorResponse{
+					Errors: []model.ValidationError{{Field: "認証", Message: "認証トークンが正しくありません"}},
+				})
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), actorKey{}, actor)))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smilemasa/go-api/model"
+)
+
+func TestMiddleware(t *testing.T) {
+	credentials := map[string]string{"admin-secret": "admin", "hanako-secret": "hanako"}
+
+	tests := []struct {
+		name          string
+		credentials   map[string]string
+		path          string
+		authorization string
+		wantStatus    int
+		wantActor     string // 空の場合は操作者が設定されない
+	}{
+		{name: "not configured", credentials: nil, path: "/dishes", wantStatus: http.StatusOK},
+		{name: "admin token", credentials: credentials, path: "/dishes", authorization: "Bearer admin-secret", wantStatus: http.StatusOK, wantActor: "admin"},
+		{name: "user token", credentials: credentials, path: "/dishes", authorization: "Bearer hanako-secret", wantStatus: http.StatusOK, wantActor: "hanako"},
+		{name: "public path", credentials: credentials, path: "/healthz", wantStatus: http.StatusOK},
+		{name: "missing token", credentials: credentials, path: "/dishes", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", credentials: credentials, path: "/dishes", authorization: "Bearer wrong", wantStatus: http.StatusUnauthorized},
+		{name: "not bearer", credentials: credentials, path: "/dishes", authorization: "Basic admin-secret", wantStatus: http.StatusUnauthorized},
+		{name: "empty bearer", credentials: credentials, path: "/dishes", authorization: "Bearer ", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotActor string
+			handler := Middleware(tt.credentials, "/healthz")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotActor, _ = Actor(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if gotActor != tt.wantActor {
+				t.Errorf("actor = %q, want %q", gotActor, tt.wantActor)
+			}
+			if tt.wantStatus != http.StatusUnauthorized {
+				return
+			}
+
+			if got := rec.Header().Get("WWW-Authenticate"); got != `Bearer realm="cookorder"` {
+				t.Errorf("WWW-Authenticate = %q", got)
+			}
+			var body model.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode error response: %v", err)
+			}
+			if len(body.Errors) != 1 || body.Errors[0].Field != "認証" {
+				t.Errorf("error response = %+v", body)
+			}
+		})
+	}
+}
@@ -1,31 +1,233 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/smilemasa/go-api/logging"
+	"github.com/smilemasa/go-api/tracing"
+	"github.com/smilemasa/go-api/translation"
+	"gopkg.in/yaml.v3"
 )
 
+// 実行環境
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+)
+
+// SSLModes データベース接続で指定できる sslmode（libpq と同じ）
+var SSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// データベースの認証方式
+const (
+	// DBAuthPassword 設定したパスワードで認証する
+	DBAuthPassword = "password"
+	// DBAuthGCPIAM Cloud SQL のIAMデータベース認証（接続ごとにアプリケーションのデフォルト認証情報でアクセストークンを取得する）
+	DBAuthGCPIAM = "gcp-iam"
+)
+
+// DBAuthMethods 指定できるデータベースの認証方式
+var DBAuthMethods = []string{DBAuthPassword, DBAuthGCPIAM}
+
 // Config アプリケーション設定
+// 既定値、YAMLファイル（CONFIG_FILE）、環境変数の順に読み込み、後に読み込んだ値で上書きする
+// 各項目の yaml タグがYAMLファイルのキー、env タグが上書きに使う環境変数（<環境変数>_FILE でファイルから読み込むこともできる）
+// 秘密情報は Secret 型にして出力時に伏せる。値に <scheme>://<ref> を指定すると登録した SecretSource から取得する
 type Config struct {
+	// Environment 実行環境（development, production）
+	Environment string `yaml:"environment" env:"ENVIRONMENT"`
+
+	// HTTPサーバー設定
+	Server ServerConfig `yaml:"server"`
+
+	// CORS設定
+	CORS CORSConfig `yaml:"cors"`
+
+	// 認証設定
+	Auth AuthConfig `yaml:"auth"`
+
 	// Database設定
-	DB struct {
-		Host     string
-		Port     int
-		User     string
-		Password string
-		Database string
-	}
+	DB DBConfig `yaml:"db"`
+
+	// ログ設定
+	Log LogConfig `yaml:"log"`
+
+	// トレース設定
+	Tracing TracingConfig `yaml:"tracing"`
 
 	// GCS設定
-	GCS struct {
-		BucketName string
+	GCS GCSConfig `yaml:"gcs"`
+
+	// 画像配信設定
+	Images ImagesConfig `yaml:"images"`
+
+	// 価格設定
+	Pricing PricingConfig `yaml:"pricing"`
+
+	// ゴミ箱設定
+	Trash TrashConfig `yaml:"trash"`
+
+	// エクスポート設定
+	Export ExportConfig `yaml:"export"`
+
+	// 翻訳設定
+	Translation TranslationConfig `yaml:"translation"`
+}
+
+// ServerConfig HTTPサーバー設定
+type ServerConfig struct {
+	// Port 待ち受けるポート
+	Port int `yaml:"port" env:"PORT"`
+	// ReadTimeout リクエスト全体（ボディを含む）の読み込みのタイムアウト
+	ReadTimeout time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	// ReadHeaderTimeout リクエストヘッダーの読み込みのタイムアウト
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	// WriteTimeout レスポンスの書き込みのタイムアウト（PDF出力など時間のかかる処理も収まる長さにする）
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	// IdleTimeout キープアライブ接続の待機時間
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// ShutdownTimeout 終了時に処理中のリクエストを待つ時間
+	// 続く停止処理（ワーカー・データベース・トレース、各0.5秒まで）と合わせて Cloud Run の猶予期間10秒に収める
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// CORSConfig CORS設定
+type CORSConfig struct {
+	// AllowedOrigins 許可するオリジン（未設定の場合は実行環境ごとの既定値）
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+}
+
+// AuthConfig 認証設定
+// admin_token・user_tokens のどちらも未設定の場合は認証しない
+type AuthConfig struct {
+	// AdminToken 管理APIの呼び出しに必要なBearerトークン（変更履歴の操作者は admin になる）
+	AdminToken Secret `yaml:"admin_token" env:"ADMIN_API_TOKEN"`
+	// UserTokens 利用者ごとのBearerトークン（<利用者ID>:<トークン> をカンマ区切り。変更履歴の操作者は利用者IDになる）
+	UserTokens Secret `yaml:"user_tokens" env:"ADMIN_API_USER_TOKENS"`
+}
+
+// AdminActor admin_token で認証したリクエストの操作者
+const AdminActor = "admin"
+
+// Credentials トークンから操作者（利用者ID）へのマップを返す（認証しない場合は空）
+func (a AuthConfig) Credentials() map[string]string {
+	credentials := map[string]string{}
+	if a.AdminToken != "" {
+		credentials[a.AdminToken.Value()] = AdminActor
+	}
+	for _, entry := range strings.Split(a.UserTokens.Value(), ",") {
+		if user, token, ok := strings.Cut(strings.TrimSpace(entry), ":"); ok && user != "" && token != "" {
+			credentials[token] = user
+		}
 	}
+	return credentials
+}
+
+// DBConfig Database設定
+type DBConfig struct {
+	// Host ホスト名、または Unix ソケットのディレクトリ（Cloud SQL Auth Proxy の場合は /cloudsql/<接続名>）
+	Host string `yaml:"host" env:"PG_HOST"`
+	// Port ポート（Unix ソケットの場合はソケットファイル名 .s.PGSQL.<ポート> に使う）
+	Port     int    `yaml:"port" env:"PG_PORT"`
+	User     string `yaml:"user" env:"PG_USER"`
+	Password Secret `yaml:"password" env:"PG_PASSWORD"`
+	Database string `yaml:"database" env:"PG_DATABASE"`
+	// Auth 認証方式（password, gcp-iam）。gcp-iam の場合 User はサービスアカウントのメールアドレスから .gserviceaccount.com を除いたもの
+	Auth string `yaml:"auth" env:"PG_AUTH"`
+	// SSLMode TLSの使用方法（disable, allow, prefer, require, verify-ca, verify-full。Unix ソケットでは使わない）
+	SSLMode string `yaml:"sslmode" env:"PG_SSLMODE"`
+	// SSLRootCert サーバー証明書を検証するCA証明書のパス（require, verify-ca, verify-full で使う。require は verify-ca と同じく検証する）
+	SSLRootCert string `yaml:"sslrootcert" env:"PG_SSLROOTCERT"`
+	// SSLCert クライアント証明書のパス（SSLKey と一緒に指定する）
+	SSLCert string `yaml:"sslcert" env:"PG_SSLCERT"`
+	// SSLKey クライアント証明書の秘密鍵のパス
+	SSLKey string `yaml:"sslkey" env:"PG_SSLKEY"`
+	// MaxConns 接続プールの最大接続数（0の場合はCPU数に応じた既定値）
+	MaxConns int `yaml:"max_conns" env:"PG_MAX_CONNS"`
+}
+
+// LogConfig ログ設定
+type LogConfig struct {
+	// Level 出力するログの最低レベル（debug, info, warn, error）
+	Level slog.Level `yaml:"level" env:"LOG_LEVEL"`
+	// Format ログの出力形式（json, text）
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig トレース設定
+type TracingConfig struct {
+	// Exporter トレースの出力先（none, stdout, otlp）
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// ServiceName トレースに付けるサービス名
+	ServiceName string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	// SampleRatio トレースを記録するリクエストの割合（0〜1、フロントエンドから引き継いだトレースはその判定に従う）
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// GCSConfig GCS設定
+type GCSConfig struct {
+	// BucketName 写真を保存するバケット（未設定の場合は写真を扱うAPIが使えない）
+	BucketName string `yaml:"bucket_name" env:"GCS_BUCKET_NAME"`
+	// SignedURLTTL 画像表示用の署名付きURLの有効期限
+	SignedURLTTL time.Duration `yaml:"signed_url_ttl" env:"GCS_SIGNED_URL_TTL"`
+	// SignedURLMinRemaining キャッシュした署名付きURLを再利用する最低残り有効期間（未設定の場合は有効期限の1/6）
+	SignedURLMinRemaining time.Duration `yaml:"signed_url_min_remaining" env:"GCS_SIGNED_URL_MIN_REMAINING"`
+	// SignedURLCacheSize キャッシュする署名付きURLの最大件数（0でキャッシュしない）
+	SignedURLCacheSize int `yaml:"signed_url_cache_size" env:"GCS_SIGNED_URL_CACHE_SIZE"`
+	// SignConcurrency 一覧表示で署名付きURLを並行して作成する件数
+	SignConcurrency int `yaml:"sign_concurrency" env:"GCS_SIGN_CONCURRENCY"`
 }
 
+// ImagesConfig 画像配信設定
+type ImagesConfig struct {
+	// ContentHashedKeys 写真を内容のハッシュを含むオブジェクト名（images/<sha256>.<拡張子>）で保存する
+	ContentHashedKeys bool `yaml:"content_hashed_keys" env:"IMAGE_CONTENT_HASHED_KEYS"`
+	// PublicBaseURL ハッシュ名の写真を署名なしで配信するURL（公開バケット・CDN・/images。未設定の場合は署名付きURL）
+	PublicBaseURL string `yaml:"public_base_url" env:"IMAGE_PUBLIC_BASE_URL"`
+}
+
+// PricingConfig 価格設定
+type PricingConfig struct {
+	// TimeZoneName ハッピーアワーなど時間帯別価格の判定に使うタイムゾーン名
+	TimeZoneName string `yaml:"timezone" env:"PRICING_TIMEZONE"`
+	// TimeZone TimeZoneName を読み込んだタイムゾーン
+	TimeZone *time.Location `yaml:"-"`
+}
+
+// TrashConfig ゴミ箱設定
+type TrashConfig struct {
+	// Retention 削除した料理をゴミ箱に保持する期間（環境変数 DISH_TRASH_RETENTION_DAYS は日数で指定する）
+	Retention time.Duration `yaml:"retention"`
+	// PurgeInterval 保持期間を過ぎた料理を完全削除する間隔
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"DISH_TRASH_PURGE_INTERVAL"`
+}
+
+// ExportConfig エクスポート設定
+type ExportConfig struct {
+	// FontPath PDFメニューに使う日本語TrueTypeフォントのパス（未設定の場合はPDF出力不可）
+	FontPath string `yaml:"font_path" env:"MENU_PDF_FONT_PATH"`
+}
+
+// TranslationConfig 翻訳設定
+type TranslationConfig struct {
+	// Backend 料理名の翻訳の下書きを作成する翻訳バックエンド（既定は用語集による辞書翻訳）
+	Backend string `yaml:"backend" env:"TRANSLATION_BACKEND"`
+}
+
+// unsetDuration 既定値を他の設定から決める期間が未設定であることを表す
+const unsetDuration time.Duration = -1
+
 var (
 	config *Config
 	once   sync.Once
@@ -37,51 +239,46 @@ func Load() (*Config, error) {
 	once.Do(func() {
 		// 開発環境でのみ.envファイルを読み込む
 		// Cloud Runなどの本番環境では環境変数が直接設定される
-		if os.Getenv("ENVIRONMENT") != "production" {
+		if os.Getenv("ENVIRONMENT") != EnvironmentProduction {
 			if loadErr := godotenv.Load(); loadErr != nil {
-				fmt.Printf("Info: .env file not found (this is normal in production): %v\n", loadErr)
+				slog.Info(".env file not found (this is normal in production)", "error", loadErr)
 			}
 		}
 
-		config = &Config{}
-
-		// Database設定
-		config.DB.Host = os.Getenv("PG_HOST")
-		config.DB.User = os.Getenv("PG_USER")
-		config.DB.Password = os.Getenv("PG_PASSWORD")
-		config.DB.Database = os.Getenv("PG_DATABASE")
-
-		portStr := os.Getenv("PG_PORT")
-		if portStr != "" {
-			config.DB.Port, _ = strconv.Atoi(portStr)
-		} else {
-			config.DB.Port = 5432 // デフォルトポート
-		}
+		c := defaults()
 
-		// GCS設定
-		config.GCS.BucketName = os.Getenv("GCS_BUCKET_NAME")
-
-		// 必須設定のバリデーション
-		var missingVars []string
-
-		if config.GCS.BucketName == "" {
-			missingVars = append(missingVars, "GCS_BUCKET_NAME")
+		// YAMLファイルの設定（未知のキーはタイプミスとしてエラーにする）
+		if path := os.Getenv("CONFIG_FILE"); path != "" {
+			if err = c.loadFile(path); err != nil {
+				return
+			}
 		}
 
-		if config.DB.Host == "" {
-			missingVars = append(missingVars, "PG_HOST")
+		// 環境変数で上書き
+		if err = applyEnv(c); err != nil {
+			return
 		}
-		if config.DB.User == "" {
-			missingVars = append(missingVars, "PG_USER")
+		if daysStr := os.Getenv("DISH_TRASH_RETENTION_DAYS"); daysStr != "" {
+			days, convErr := strconv.Atoi(daysStr)
+			if convErr != nil {
+				err = fmt.Errorf("invalid DISH_TRASH_RETENTION_DAYS %q: must be an integer", daysStr)
+				return
+			}
+			c.Trash.Retention = time.Duration(days) * 24 * time.Hour
 		}
-		if config.DB.Database == "" {
-			missingVars = append(missingVars, "PG_DATABASE")
+
+		// 秘密情報の参照を取得元から読み込む
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err = resolveSecrets(ctx, c); err != nil {
+			return
 		}
 
-		if len(missingVars) > 0 {
-			err = fmt.Errorf("missing required environment variables: %v", missingVars)
+		c.resolveDefaults()
+		if err = c.validate(); err != nil {
 			return
 		}
+		config = c
 	})
 
 	return config, err
@@ -95,14 +292,280 @@ func Get() *Config {
 	return config
 }
 
+// defaults 既定値の設定を作成する
+func defaults() *Config {
+	c := &Config{}
+	c.Environment = EnvironmentDevelopment
+
+	c.Server.Port = 8080
+	c.Server.ReadTimeout = 30 * time.Second
+	c.Server.ReadHeaderTimeout = 10 * time.Second
+	c.Server.WriteTimeout = 60 * time.Second
+	c.Server.IdleTimeout = 120 * time.Second
+	c.Server.ShutdownTimeout = 8 * time.Second // Cloud Run は SIGTERM の10秒後に強制終了するため、残りを停止処理に使う
+
+	c.DB.Port = 5432
+	c.DB.Auth = DBAuthPassword
+	c.DB.SSLMode = "disable"
+
+	c.Log.Level = slog.LevelInfo
+	c.Log.Format = logging.FormatJSON
+
+	c.Tracing.Exporter = tracing.ExporterNone
+	c.Tracing.ServiceName = "cookorder-api"
+	c.Tracing.SampleRatio = 1 // すべて記録
+
+	c.GCS.SignedURLTTL = time.Hour
+	c.GCS.SignedURLMinRemaining = unsetDuration
+	c.GCS.SignedURLCacheSize = 10000
+	c.GCS.SignConcurrency = 8
+
+	c.Pricing.TimeZoneName = "Asia/Tokyo"
+
+	c.Trash.Retention = 30 * 24 * time.Hour
+	c.Trash.PurgeInterval = 24 * time.Hour
+
+	c.Translation.Backend = translation.DefaultBackend
+
+	return c
+}
+
+// loadFile YAMLファイルの設定を読み込む
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolveDefaults 他の設定から決まる既定値を設定する
+func (c *Config) resolveDefaults() {
+	if c.GCS.SignedURLMinRemaining == unsetDuration {
+		c.GCS.SignedURLMinRemaining = c.GCS.SignedURLTTL / 6 // 1時間なら10分
+	}
+	c.Images.PublicBaseURL = strings.TrimRight(c.Images.PublicBaseURL, "/")
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		if c.IsProduction() {
+			// 本番環境のデフォルト（実際のドメインに変更してください）
+			c.CORS.AllowedOrigins = []string{
+				"https://your-production-domain.com",
+				"https://www.your-production-domain.com",
+			}
+		} else {
+			// 開発環境のデフォルト
+			c.CORS.AllowedOrigins = []string{
+				"http://localhost:3000",
+				"http://localhost:3001",
+				"http://127.0.0.1:3000",
+			}
+		}
+	}
+}
+
+// validate 設定値を検証し、問題をすべてまとめたエラーを返す
+func (c *Config) validate() error {
+	var errs []error
+	invalid := func(key string, value any, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("invalid %s %v: %s", key, value, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Environment != EnvironmentDevelopment && c.Environment != EnvironmentProduction {
+		invalid("environment", strconv.Quote(c.Environment), "must be %s or %s", EnvironmentDevelopment, EnvironmentProduction)
+	}
+
+	// HTTPサーバー設定
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		invalid("server.port", c.Server.Port, "must be between 1 and 65535")
+	}
+	for key, d := range map[string]time.Duration{
+		"server.read_timeout":        c.Server.ReadTimeout,
+		"server.read_header_timeout": c.Server.ReadHeaderTimeout,
+		"server.write_timeout":       c.Server.WriteTimeout,
+		"server.idle_timeout":        c.Server.IdleTimeout,
+		"server.shutdown_timeout":    c.Server.ShutdownTimeout,
+	} {
+		if d <= 0 {
+			invalid(key, d, "must be a positive duration such as 30s")
+		}
+	}
+
+	// CORS設定
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			invalid("cors.allowed_origins", strconv.Quote(origin), "must be * or an origin such as https://example.com")
+		}
+	}
+
+	// 認証設定（トークンは秘密情報のため値を表示しない）
+	if c.Auth.UserTokens != "" {
+		seen := map[string]bool{c.Auth.AdminToken.Value(): c.Auth.AdminToken != ""}
+		for i, entry := range strings.Split(c.Auth.UserTokens.Value(), ",") {
+			user, token, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			switch {
+			case !ok || user == "" || token == "":
+				invalid("auth.user_tokens", fmt.Sprintf("entry %d", i+1), "must be <user id>:<token>")
+			case user == AdminActor:
+				invalid("auth.user_tokens", fmt.Sprintf("entry %d", i+1), "user id %s is reserved for auth.admin_token", AdminActor)
+			case seen[token]:
+				invalid("auth.user_tokens", fmt.Sprintf("entry %d", i+1), "token must be unique")
+			}
+			seen[token] = true
+		}
+	}
+
+	// Database設定
+	if c.DB.Host == "" {
+		invalid("db.host", `""`, "required")
+	}
+	if c.DB.Port < 1 || c.DB.Port > 65535 {
+		invalid("db.port", c.DB.Port, "must be between 1 and 65535")
+	}
+	if c.DB.User == "" {
+		invalid("db.user", `""`, "required")
+	}
+	if c.DB.Database == "" {
+		invalid("db.database", `""`, "required")
+	}
+	if !slices.Contains(DBAuthMethods, c.DB.Auth) {
+		invalid("db.auth", strconv.Quote(c.DB.Auth), "must be one of %v", DBAuthMethods)
+	}
+	if c.DB.Auth == DBAuthGCPIAM && c.DB.Password != "" {
+		invalid("db.password", c.DB.Password, "must be empty when db.auth is %s", DBAuthGCPIAM)
+	}
+	if !slices.Contains(SSLModes, c.DB.SSLMode) {
+		invalid("db.sslmode", strconv.Quote(c.DB.SSLMode), "must be one of %v", SSLModes)
+	}
+	if c.DB.SSLRootCert != "" && !slices.Contains([]string{"require", "verify-ca", "verify-full"}, c.DB.SSLMode) {
+		invalid("db.sslrootcert", strconv.Quote(c.DB.SSLRootCert), "requires db.sslmode require, verify-ca or verify-full")
+	}
+	if (c.DB.SSLCert == "") != (c.DB.SSLKey == "") {
+		invalid("db.sslcert", strconv.Quote(c.DB.SSLCert), "must be set together with db.sslkey")
+	}
+	for _, f := range []struct{ key, path string }{
+		{"db.sslrootcert", c.DB.SSLRootCert},
+		{"db.sslcert", c.DB.SSLCert},
+		{"db.sslkey", c.DB.SSLKey},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			invalid(f.key, strconv.Quote(f.path), "%v", err)
+		}
+	}
+	if c.DB.MaxConns < 0 {
+		invalid("db.max_conns", c.DB.MaxConns, "must be a non-negative integer (0 uses the default)")
+	}
+
+	// ログ設定
+	if c.Log.Format != logging.FormatJSON && c.Log.Format != logging.FormatText {
+		invalid("log.format", strconv.Quote(c.Log.Format), "must be json or text")
+	}
+
+	// トレース設定
+	if !slices.Contains(tracing.Exporters, c.Tracing.Exporter) {
+		invalid("tracing.exporter", strconv.Quote(c.Tracing.Exporter), "must be one of %v", tracing.Exporters)
+	}
+	if c.Tracing.ServiceName == "" {
+		invalid("tracing.service_name", `""`, "required")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", c.Tracing.SampleRatio, "must be a number between 0 and 1")
+	}
+
+	// GCS設定
+	// V4署名の有効期限は最大7日
+	if c.GCS.SignedURLTTL <= 0 || c.GCS.SignedURLTTL > 7*24*time.Hour {
+		invalid("gcs.signed_url_ttl", c.GCS.SignedURLTTL, "must be a positive duration up to 168h")
+	}
+	if c.GCS.SignedURLMinRemaining < 0 || c.GCS.SignedURLMinRemaining >= c.GCS.SignedURLTTL {
+		invalid("gcs.signed_url_min_remaining", c.GCS.SignedURLMinRemaining, "must be a non-negative duration shorter than gcs.signed_url_ttl")
+	}
+	if c.GCS.SignedURLCacheSize < 0 {
+		invalid("gcs.signed_url_cache_size", c.GCS.SignedURLCacheSize, "must be a non-negative integer")
+	}
+	if c.GCS.SignConcurrency < 1 {
+		invalid("gcs.sign_concurrency", c.GCS.SignConcurrency, "must be a positive integer")
+	}
+
+	// 画像配信設定
+	if c.Images.PublicBaseURL != "" {
+		u, err := url.Parse(c.Images.PublicBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("images.public_base_url", strconv.Quote(c.Images.PublicBaseURL), "must be an absolute http(s) URL")
+		}
+	}
+
+	// 価格設定
+	tz, err := time.LoadLocation(c.Pricing.TimeZoneName)
+	if err != nil {
+		invalid("pricing.timezone", strconv.Quote(c.Pricing.TimeZoneName), "%v", err)
+	}
+	c.Pricing.TimeZone = tz
+
+	// ゴミ箱設定
+	if c.Trash.Retention < 24*time.Hour {
+		invalid("trash.retention", c.Trash.Retention, "must be at least one day")
+	}
+	if c.Trash.PurgeInterval <= 0 {
+		invalid("trash.purge_interval", c.Trash.PurgeInterval, "must be a positive duration such as 24h")
+	}
+
+	// エクスポート設定
+	if c.Export.FontPath != "" {
+		if _, err := os.Stat(c.Export.FontPath); err != nil {
+			invalid("export.font_path", strconv.Quote(c.Export.FontPath), "%v", err)
+		}
+	}
+
+	// 翻訳設定
+	if !slices.Contains(translation.Backends(), c.Translation.Backend) {
+		invalid("translation.backend", strconv.Quote(c.Translation.Backend), "must be one of %v", translation.Backends())
+	}
+
+	return errors.Join(errs...)
+}
+
+// IsProduction 本番環境かどうか
+func (c *Config) IsProduction() bool {
+	return c.Environment == EnvironmentProduction
+}
+
 // GetDatabaseDSN データベース接続文字列を取得
+// ログやエラーに出ても漏れないようにパスワードは含めない（接続設定の Password に c.DB.Password.Value() を設定する）
+// Host が / で始まる場合は Unix ソケットで接続する
 func (c *Config) GetDatabaseDSN() string {
-	return fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		c.DB.Host,
+	dsn := fmt.Sprintf(
+		"host=%s port=%d user=%s dbname=%s sslmode=%s",
+		dsnValue(c.DB.Host),
 		c.DB.Port,
-		c.DB.User,
-		c.DB.Password,
-		c.DB.Database,
+		dsnValue(c.DB.User),
+		dsnValue(c.DB.Database),
+		c.DB.SSLMode,
 	)
+	if c.DB.SSLRootCert != "" {
+		dsn += " sslrootcert=" + dsnValue(c.DB.SSLRootCert)
+	}
+	if c.DB.SSLCert != "" {
+		dsn += " sslcert=" + dsnValue(c.DB.SSLCert) + " sslkey=" + dsnValue(c.DB.SSLKey)
+	}
+	return dsn
+}
+
+// dsnValue 接続文字列の値を引用符で囲む（空白や引用符を含む値でも壊れないようにする）
+func dsnValue(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
 }
@@ -0,0 +1,311 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig 検証を通る設定（既定値にデータベースの必須項目を設定したもの）
+func validConfig() *Config {
+	c := defaults()
+	c.DB.Host = "localhost"
+	c.DB.User = "app_user"
+	c.DB.Database = "cookorder"
+	c.resolveDefaults()
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "root.crt")
+	if err := os.WriteFile(certFile, []byte("certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr []string // エラーメッセージに含まれる文字列（空の場合は検証を通る）
+	}{
+		{name: "defaults", modify: func(c *Config) {}},
+		{
+			name:    "unknown environment",
+			modify:  func(c *Config) { c.Environment = "staging" },
+			wantErr: []string{`invalid environment "staging"`},
+		},
+		{
+			name:    "port out of range",
+			modify:  func(c *Config) { c.Server.Port = 70000 },
+			wantErr: []string{"invalid server.port 70000"},
+		},
+		{
+			name:    "non-positive timeout",
+			modify:  func(c *Config) { c.Server.ShutdownTimeout = 0 },
+			wantErr: []string{"invalid server.shutdown_timeout 0s"},
+		},
+		{
+			name: "wildcard and origins",
+			modify: func(c *Config) {
+				c.CORS.AllowedOrigins = []string{"*", "https://example.com", "http://localhost:3000/"}
+			},
+		},
+		{
+			name:    "origin with path",
+			modify:  func(c *Config) { c.CORS.AllowedOrigins = []string{"https://example.com/app"} },
+			wantErr: []string{`invalid cors.allowed_origins "https://example.com/app"`},
+		},
+		{
+			name: "user tokens",
+			modify: func(c *Config) {
+				c.Auth.AdminToken = "admin-secret"
+				c.Auth.UserTokens = "hanako:hanako-secret, taro:taro-secret"
+			},
+		},
+		{
+			name:    "user token without user id",
+			modify:  func(c *Config) { c.Auth.UserTokens = "hanako-secret" },
+			wantErr: []string{"invalid auth.user_tokens entry 1: must be <user id>:<token>"},
+		},
+		{
+			name:    "reserved admin user id",
+			modify:  func(c *Config) { c.Auth.UserTokens = "hanako:hanako-secret,admin:other-secret" },
+			wantErr: []string{"invalid auth.user_tokens entry 2: user id admin is reserved"},
+		},
+		{
+			name: "token shared with admin token",
+			modify: func(c *Config) {
+				c.Auth.AdminToken = "shared-secret"
+				c.Auth.UserTokens = "hanako:shared-secret"
+			},
+			wantErr: []string{"invalid auth.user_tokens entry 1: token must be unique"},
+		},
+		{
+			name:    "duplicate user token",
+			modify:  func(c *Config) { c.Auth.UserTokens = "hanako:same-secret,taro:same-secret" },
+			wantErr: []string{"invalid auth.user_tokens entry 2: token must be unique"},
+		},
+		{
+			name: "database required fields",
+			modify: func(c *Config) {
+				c.DB.Host = ""
+				c.DB.User = ""
+				c.DB.Database = ""
+			},
+			wantErr: []string{`invalid db.host "": required`, `invalid db.user "": required`, `invalid db.database "": required`},
+		},
+		{
+			name: "password with IAM auth",
+			modify: func(c *Config) {
+				c.DB.Auth = DBAuthGCPIAM
+				c.DB.Password = "db-secret"
+			},
+			wantErr: []string{"invalid db.password [REDACTED]: must be empty when db.auth is gcp-iam"},
+		},
+		{
+			name:    "unknown sslmode",
+			modify:  func(c *Config) { c.DB.SSLMode = "on" },
+			wantErr: []string{`invalid db.sslmode "on"`},
+		},
+		{
+			name: "sslrootcert with verify-full",
+			modify: func(c *Config) {
+				c.DB.SSLMode = "verify-full"
+				c.DB.SSLRootCert = certFile
+			},
+		},
+		{
+			name: "sslrootcert with require",
+			modify: func(c *Config) {
+				c.DB.SSLMode = "require"
+				c.DB.SSLRootCert = certFile
+			},
+		},
+		{
+			name: "sslrootcert with prefer",
+			modify: func(c *Config) {
+				c.DB.SSLMode = "prefer"
+				c.DB.SSLRootCert = certFile
+			},
+			wantErr: []string{"requires db.sslmode require, verify-ca or verify-full"},
+		},
+		{
+			name: "sslcert without sslkey",
+			modify: func(c *Config) {
+				c.DB.SSLMode = "verify-full"
+				c.DB.SSLCert = certFile
+			},
+			wantErr: []string{"must be set together with db.sslkey"},
+		},
+		{
+			name: "missing certificate file",
+			modify: func(c *Config) {
+				c.DB.SSLMode = "verify-full"
+				c.DB.SSLRootCert = filepath.Join(t.TempDir(), "missing.crt")
+			},
+			wantErr: []string{"invalid db.sslrootcert"},
+		},
+		{
+			name:    "sample ratio out of range",
+			modify:  func(c *Config) { c.Tracing.SampleRatio = 1.5 },
+			wantErr: []string{"invalid tracing.sample_ratio 1.5"},
+		},
+		{
+			name:    "min remaining not shorter than ttl",
+			modify:  func(c *Config) { c.GCS.SignedURLMinRemaining = c.GCS.SignedURLTTL },
+			wantErr: []string{"invalid gcs.signed_url_min_remaining 1h0m0s"},
+		},
+		{
+			name:    "relative public base url",
+			modify:  func(c *Config) { c.Images.PublicBaseURL = "/images" },
+			wantErr: []string{`invalid images.public_base_url "/images"`},
+		},
+		{
+			name:    "unknown time zone",
+			modify:  func(c *Config) { c.Pricing.TimeZoneName = "Mars/Olympus" },
+			wantErr: []string{`invalid pricing.timezone "Mars/Olympus"`},
+		},
+		{
+			name:    "retention shorter than a day",
+			modify:  func(c *Config) { c.Trash.Retention = time.Hour },
+			wantErr: []string{"invalid trash.retention 1h0m0s: must be at least one day"},
+		},
+		{
+			name:    "unknown translation backend",
+			modify:  func(c *Config) { c.Translation.Backend = "unknown" },
+			wantErr: []string{`invalid translation.backend "unknown"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(c)
+			err := c.validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validate() = nil, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("validate() = %v, want containing %q", err, want)
+				}
+			}
+			// 秘密情報はエラーに含めない
+			secrets := []string{c.Auth.AdminToken.Value(), c.DB.Password.Value()}
+			for _, entry := range strings.Split(c.Auth.UserTokens.Value(), ",") {
+				_, token, _ := strings.Cut(entry, ":")
+				secrets = append(secrets, token)
+			}
+			for _, secret := range secrets {
+				if secret != "" && strings.Contains(err.Error(), secret) {
+					t.Errorf("validate() = %v, contains secret %q", err, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSetsTimeZone(t *testing.T) {
+	c := validConfig()
+	if err := c.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Pricing.TimeZone == nil || c.Pricing.TimeZone.String() != "Asia/Tokyo" {
+		t.Errorf("Pricing.TimeZone = %v, want Asia/Tokyo", c.Pricing.TimeZone)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	t.Setenv("HTTP_READ_TIMEOUT", "45s")
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://a.example.com, ,https://b.example.com ")
+	t.Setenv("ADMIN_API_TOKEN", "admin-secret")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+	t.Setenv("IMAGE_CONTENT_HASHED_KEYS", "true")
+	t.Setenv("PG_HOST", "")
+
+	c := defaults()
+	c.DB.Host = "from-file"
+	if err := applyEnv(c); err != nil {
+		t.Fatalf("applyEnv() = %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"Server.Port", c.Server.Port, 9090},
+		{"Server.ReadTimeout", c.Server.ReadTimeout, 45 * time.Second},
+		{"Server.WriteTimeout", c.Server.WriteTimeout, 60 * time.Second},
+		{"CORS.AllowedOrigins", c.CORS.AllowedOrigins, []string{"https://a.example.com", "https://b.example.com"}},
+		{"Auth.AdminToken", c.Auth.AdminToken, Secret("admin-secret")},
+		{"Log.Level", c.Log.Level, slog.LevelDebug},
+		{"Tracing.SampleRatio", c.Tracing.SampleRatio, 0.25},
+		{"Images.ContentHashedKeys", c.Images.ContentHashedKeys, true},
+		{"DB.Host", c.DB.Host, "from-file"}, // 空の環境変数では上書きしない
+	}
+	for _, check := range checks {
+		if !reflect.DeepEqual(check.got, check.want) {
+			t.Errorf("%s = %v, want %v", check.name, check.got, check.want)
+		}
+	}
+}
+
+func TestApplyEnvInvalid(t *testing.T) {
+	tests := []struct {
+		name, env, value, wantErr string
+	}{
+		{"integer", "PORT", "eighty", `invalid PORT "eighty": must be an integer`},
+		{"duration", "HTTP_READ_TIMEOUT", "30", `invalid HTTP_READ_TIMEOUT "30": must be a duration such as 30s`},
+		{"number", "TRACING_SAMPLE_RATIO", "half", `invalid TRACING_SAMPLE_RATIO "half": must be a number`},
+		{"bool", "IMAGE_CONTENT_HASHED_KEYS", "yes", `invalid IMAGE_CONTENT_HASHED_KEYS "yes": must be true or false`},
+		{"log level", "LOG_LEVEL", "verbose", `invalid LOG_LEVEL "verbose"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			err := applyEnv(defaults())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("applyEnv() = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	tests := []struct {
+		name string
+		auth AuthConfig
+		want map[string]string
+	}{
+		{name: "not configured", auth: AuthConfig{}, want: map[string]string{}},
+		{name: "admin token", auth: AuthConfig{AdminToken: "admin-secret"}, want: map[string]string{"admin-secret": AdminActor}},
+		{
+			name: "admin and user tokens",
+			auth: AuthConfig{AdminToken: "admin-secret", UserTokens: " hanako:hanako-secret ,taro:taro:secret"},
+			want: map[string]string{"admin-secret": AdminActor, "hanako-secret": "hanako", "taro:secret": "taro"},
+		},
+		{
+			name: "malformed entries are ignored",
+			auth: AuthConfig{UserTokens: "hanako-secret,:token,taro:"},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.auth.Credentials(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Credentials() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
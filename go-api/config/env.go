@@ -0,0 +1,114 @@
+package config
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// applyEnv env タグの環境変数が設定されている項目を上書きする
+// <環境変数>_FILE が設定されている場合はそのファイルの内容を値にする（マウントしたシークレット用）
+func applyEnv(c *Config) error {
+	var err error
+	walkFields(reflect.ValueOf(c).Elem(), func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("env")
+		if name == "" || err != nil {
+			return
+		}
+		str := os.Getenv(name)
+		if path := os.Getenv(name + "_FILE"); path != "" {
+			if str != "" {
+				err = fmt.Errorf("both %s and %s_FILE are set: set only one", name, name)
+				return
+			}
+			var readErr error
+			if str, readErr = (FileSource{}).Resolve(context.Background(), path); readErr != nil {
+				err = fmt.Errorf("invalid %s_FILE: %w", name, readErr)
+				return
+			}
+			if setErr := setValue(value, str); setErr != nil {
+				// ファイルの内容は秘密情報の可能性があるため表示しない
+				err = fmt.Errorf("invalid %s_FILE %q: %w", name, path, setErr)
+			}
+			return
+		}
+		if str == "" {
+			return
+		}
+		if setErr := setValue(value, str); setErr != nil {
+			err = fmt.Errorf("invalid %s %q: %w", name, str, setErr)
+		}
+	})
+	return err
+}
+
+// setValue 環境変数の文字列を項目の型に変換して設定する
+func setValue(value reflect.Value, str string) error {
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+	}
+
+	switch {
+	case value.Type() == durationType:
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 30s")
+		}
+		value.SetInt(int64(d))
+	case value.Kind() == reflect.String:
+		value.SetString(str)
+	case value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(str)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		value.SetInt(int64(n))
+	case value.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		value.SetFloat(f)
+	case value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+		value.SetBool(b)
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		// カンマ区切りの文字列をスライスに変換
+		var items []string
+		for _, item := range strings.Split(str, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", value.Type())
+	}
+	return nil
+}
+
+// walkFields 設定の各項目（入れ子の構造体の中の項目を含む）に fn を呼ぶ
+func walkFields(v reflect.Value, fn func(field reflect.StructField, value reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field, value := t.Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Tag.Get("env") == "" {
+			walkFields(value, fn)
+			continue
+		}
+		fn(field, value)
+	}
+}
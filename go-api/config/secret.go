@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Secret パスワードやトークンなどの秘密情報
+// fmt・slog・JSON・YAML のどれで出力しても伏せた値になる。実際の値は Value で取得する
+type Secret string
+
+// redacted 出力時に伏せた秘密情報の代わりに表示する文字列
+const redacted = "[REDACTED]"
+
+// Value 秘密情報の実際の値を返す
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String 伏せた値を返す（未設定の場合は空文字）
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString %#v でも伏せた値を出力する
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// MarshalText JSON・YAMLに伏せた値を出力する
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// LogValue ログに伏せた値を出力する
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// SecretSource 秘密情報の取得元（マウントしたファイル、GCP Secret Manager など）
+type SecretSource interface {
+	// Resolve ref が指す秘密情報を取得する
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// FileSource ファイルに書かれた秘密情報を読み込む（Kubernetes・Cloud Run でマウントしたシークレット用）
+type FileSource struct{}
+
+// Resolve ref のパスのファイルを読み込む（末尾の改行は取り除く）
+func (FileSource) Resolve(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+var (
+	secretSourcesMu sync.RWMutex
+	// secretSources 参照のスキームごとの秘密情報の取得元
+	secretSources = map[string]SecretSource{
+		"file": FileSource{},
+	}
+)
+
+// RegisterSecretSource <scheme>://<ref> 形式の秘密情報の参照を src で取得するように登録する
+// GCP Secret Manager などの取得元は Load の前に登録する
+func RegisterSecretSource(scheme string, src SecretSource) {
+	secretSourcesMu.Lock()
+	defer secretSourcesMu.Unlock()
+	secretSources[scheme] = src
+}
+
+// secretSource 参照のスキームに対応する取得元を返す
+func secretSource(scheme string) (SecretSource, bool) {
+	secretSourcesMu.RLock()
+	defer secretSourcesMu.RUnlock()
+	src, ok := secretSources[scheme]
+	return src, ok
+}
+
+var secretType = reflect.TypeOf(Secret(""))
+
+// resolveSecrets <scheme>://<ref> 形式で指定された秘密情報を登録した取得元から読み込む
+// （例: file:///run/secrets/pg_password）
+func resolveSecrets(ctx context.Context, c *Config) error {
+	var err error
+	walkFields(reflect.ValueOf(c).Elem(), func(field reflect.StructField, value reflect.Value) {
+		if value.Type() != secretType || err != nil {
+			return
+		}
+		scheme, ref, ok := strings.Cut(value.String(), "://")
+		if !ok {
+			return
+		}
+		src, ok := secretSource(scheme)
+		if !ok {
+			return
+		}
+		secret, resolveErr := src.Resolve(ctx, ref)
+		if resolveErr != nil {
+			err = fmt.Errorf("failed to resolve secret %s from %s: %w", field.Tag.Get("env"), scheme, resolveErr)
+			return
+		}
+		value.SetString(secret)
+	})
+	return err
+}
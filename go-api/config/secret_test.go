@@ -0,0 +1,191 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	const value = "super-secret-token"
+	auth := AuthConfig{AdminToken: value}
+
+	var logBuf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logBuf, nil)).Info("config", "token", auth.AdminToken, "auth", auth)
+
+	jsonOut, err := json.Marshal(auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlOut, err := yaml.Marshal(auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var configOut bytes.Buffer
+	c := validConfig()
+	c.Auth.AdminToken = value
+	c.DB.Password = value
+	if err := c.WriteYAML(&configOut); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := map[string]string{
+		"%v":        fmt.Sprintf("%v", auth.AdminToken),
+		"%s":        fmt.Sprintf("%s", auth.AdminToken),
+		"%+v":       fmt.Sprintf("%+v", auth),
+		"%#v":       fmt.Sprintf("%#v", auth),
+		"slog":      logBuf.String(),
+		"json":      string(jsonOut),
+		"yaml":      string(yamlOut),
+		"WriteYAML": configOut.String(),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, value) {
+			t.Errorf("%s output contains the secret: %s", name, out)
+		}
+		if !strings.Contains(out, redacted) {
+			t.Errorf("%s output = %s, want %s", name, out, redacted)
+		}
+	}
+
+	if got := auth.AdminToken.Value(); got != value {
+		t.Errorf("Value() = %q, want %q", got, value)
+	}
+	if got := fmt.Sprint(Secret("")); got != "" {
+		t.Errorf("empty secret prints %q, want empty", got)
+	}
+}
+
+func TestApplyEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("reads the file without trailing newline", func(t *testing.T) {
+		t.Setenv("PG_PASSWORD_FILE", write("pg_password", "db-secret\r\n"))
+		c := defaults()
+		if err := applyEnv(c); err != nil {
+			t.Fatalf("applyEnv() = %v", err)
+		}
+		if got := c.DB.Password.Value(); got != "db-secret" {
+			t.Errorf("DB.Password = %q, want db-secret", got)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "both variable and file",
+			env:     map[string]string{"ADMIN_API_TOKEN": "admin-secret", "ADMIN_API_TOKEN_FILE": write("admin_token", "admin-secret")},
+			wantErr: "both ADMIN_API_TOKEN and ADMIN_API_TOKEN_FILE are set",
+		},
+		{
+			name:    "missing file",
+			env:     map[string]string{"PG_PASSWORD_FILE": filepath.Join(dir, "missing")},
+			wantErr: "invalid PG_PASSWORD_FILE",
+		},
+		{
+			name:    "invalid file content is not shown",
+			env:     map[string]string{"PORT_FILE": write("port", "secret-port")},
+			wantErr: "invalid PORT_FILE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			err := applyEnv(defaults())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("applyEnv() = %v, want containing %q", err, tt.wantErr)
+			}
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("applyEnv() = %v, contains the file content", err)
+			}
+		})
+	}
+}
+
+// mapSource 秘密情報の取得元（テスト用）
+type mapSource map[string]string
+
+func (s mapSource) Resolve(_ context.Context, ref string) (string, error) {
+	secret, ok := s[ref]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
+
+func TestResolveSecrets(t *testing.T) {
+	RegisterSecretSource("test", mapSource{"projects/p/secrets/admin-token": "admin-secret"})
+	passwordFile := filepath.Join(t.TempDir(), "pg_password")
+	if err := os.WriteFile(passwordFile, []byte("db-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		adminToken   Secret
+		password     Secret
+		wantToken    string
+		wantPassword string
+		wantErr      string
+	}{
+		{
+			name:       "registered source and file",
+			adminToken: "test://projects/p/secrets/admin-token", password: Secret("file://" + passwordFile),
+			wantToken: "admin-secret", wantPassword: "db-secret",
+		},
+		{
+			name:       "plain values and unknown schemes are kept",
+			adminToken: "plain-token", password: "vault://secret/db",
+			wantToken: "plain-token", wantPassword: "vault://secret/db",
+		},
+		{
+			name:       "resolve error",
+			adminToken: "test://projects/p/secrets/missing",
+			wantErr:    "failed to resolve secret ADMIN_API_TOKEN from test: not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := defaults()
+			c.Auth.AdminToken = tt.adminToken
+			c.DB.Password = tt.password
+			err := resolveSecrets(context.Background(), c)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveSecrets() = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSecrets() = %v", err)
+			}
+			if got := c.Auth.AdminToken.Value(); got != tt.wantToken {
+				t.Errorf("Auth.AdminToken = %q, want %q", got, tt.wantToken)
+			}
+			if got := c.DB.Password.Value(); got != tt.wantPassword {
+				t.Errorf("DB.Password = %q, want %q", got, tt.wantPassword)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/config"
+	"golang.org/x/oauth2/google"
+)
+
+// cloudSQLLoginScope Cloud SQL のIAMデータベース認証に使うアクセストークンのスコープ
+const cloudSQLLoginScope = "https://www.googleapis.com/auth/sqlservice.login"
+
+// PasswordProvider 接続ごとに使うパスワードを返す（IAM認証のアクセストークンなど有効期限のある認証情報用）
+type PasswordProvider func(ctx context.Context) (string, error)
+
+var (
+	passwordProviderMu sync.Mutex
+	passwordProvider   PasswordProvider
+)
+
+// SetPasswordProvider 接続時のパスワードを p で取得するようにする（設定の db.auth より優先する）
+// 接続プールを作成する前に呼ぶ
+func SetPasswordProvider(p PasswordProvider) {
+	passwordProviderMu.Lock()
+	defer passwordProviderMu.Unlock()
+	passwordProvider = p
+}
+
+// currentPasswordProvider 設定した PasswordProvider、または db.auth に応じた取得方法を返す（固定のパスワードの場合は nil）
+func currentPasswordProvider(cfg *config.Config) (PasswordProvider, error) {
+	passwordProviderMu.Lock()
+	defer passwordProviderMu.Unlock()
+	if passwordProvider != nil {
+		return passwordProvider, nil
+	}
+
+	switch cfg.DB.Auth {
+	case config.DBAuthGCPIAM:
+		// トークンは有効期限まで再利用され、期限が近づくと取得し直される
+		// トークンソースは接続プールと同じ期間使うため、呼び出し元の期限付きのコンテキストは渡さない
+		ts, err := google.DefaultTokenSource(context.Background(), cloudSQLLoginScope)
+		if err != nil {
+			return nil, fmt.Errorf("IAM認証のトークン取得の準備に失敗しました: %w", err)
+		}
+		passwordProvider = func(context.Context) (string, error) {
+			token, err := ts.Token()
+			if err != nil {
+				return "", fmt.Errorf("IAM認証のトークン取得に失敗しました: %w", err)
+			}
+			return token.AccessToken, nil
+		}
+		return passwordProvider, nil
+	default:
+		return nil, nil
+	}
+}
+
+// beforeConnect 接続ごとにパスワードを取得して接続設定に設定する関数を返す（固定のパスワードの場合は nil）
+func beforeConnect(cfg *config.Config) (func(context.Context, *pgx.ConnConfig) error, error) {
+	provider, err := currentPasswordProvider(cfg)
+	if err != nil || provider == nil {
+		return nil, err
+	}
+	return func(ctx context.Context, cc *pgx.ConnConfig) error {
+		password, err := provider(ctx)
+		if err != nil {
+			return err
+		}
+		cc.Password = password
+		return nil
+	}, nil
+}
@@ -3,17 +3,38 @@ package db
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/smilemasa/go-api/config"
+	"github.com/smilemasa/go-api/metrics"
 )
 
-func ConnectDB() (*pgx.Conn, error) {
-	cfg := config.Get()
+var (
+	pool     *pgxpool.Pool
+	poolOnce sync.Once
+	poolErr  error
+)
+
+// ConnectDB 接続プールから接続を取得する（使い終わったら Release で返却する）
+// 接続プールは初回の呼び出し時に作成される
+func ConnectDB() (*pgxpool.Conn, error) {
+	return acquire(context.Background())
+}
 
-	conn, err := pgx.Connect(context.Background(), cfg.GetDatabaseDSN())
-	fmt.Printf("Connecting to database at %s:%d\n", cfg.DB.Host, cfg.DB.Port)
+// acquire ctx の期限内に接続プールから接続を取得する
+func acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	poolOnce.Do(func() {
+		pool, poolErr = newPool(context.Background())
+	})
+	if poolErr != nil {
+		return nil, fmt.Errorf("データベース接続失敗: %v", poolErr)
+	}
+
+	conn, err := pool.Acquire(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("データベース接続失敗: %v", err)
 	}
@@ -21,19 +42,79 @@ func ConnectDB() (*pgx.Conn, error) {
 	return conn, nil
 }
 
+// Ping 接続プールからデータベースに接続できるか確認する（ヘルスチェック用）
+func Ping(ctx context.Context) error {
+	conn, err := acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	return conn.Ping(ctx)
+}
+
+// newPool 設定に従って接続プールを作成し、クエリ時間と接続プールの状態をメトリクスに登録する
+func newPool(ctx context.Context) (*pgxpool.Pool, error) {
+	cfg := config.Get()
+
+	poolConfig, err := pgxpool.ParseConfig(cfg.GetDatabaseDSN())
+	if err != nil {
+		return nil, err
+	}
+	// パスワードは接続文字列に含めず、接続設定に直接渡す
+	poolConfig.ConnConfig.Password = cfg.DB.Password.Value()
+	// IAM認証などでは接続ごとにトークンを取得する
+	poolConfig.BeforeConnect, err = beforeConnect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DB.MaxConns > 0 {
+		poolConfig.MaxConns = int32(cfg.DB.MaxConns)
+	}
+	poolConfig.ConnConfig.Tracer = queryTracer{}
+
+	p, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+	metrics.RegisterPool(p.Stat)
+
+	return p, nil
+}
+
+// ClosePool 接続プールを閉じる（アプリケーション終了時に呼ぶ）
+func ClosePool() {
+	if pool != nil {
+		pool.Close()
+	}
+}
+
 // TestConnection データベースとの疎通確認を行う
 func TestConnection() error {
 	cfg := config.Get()
 
-	fmt.Printf("データベース疎通確認を開始します...\n")
-	fmt.Printf("接続先: %s:%d (データベース: %s)\n", cfg.DB.Host, cfg.DB.Port, cfg.DB.Database)
+	slog.Info("データベース疎通確認を開始します", "host", cfg.DB.Host, "port", cfg.DB.Port, "database", cfg.DB.Database)
 
 	// タイムアウト付きのコンテキストを作成（10秒）
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// データベースに接続
-	conn, err := pgx.Connect(ctx, cfg.GetDatabaseDSN())
+	connConfig, err := pgx.ParseConfig(cfg.GetDatabaseDSN())
+	if err != nil {
+		return fmt.Errorf("データベース接続設定が不正です: %w", err)
+	}
+	connConfig.Password = cfg.DB.Password.Value()
+	setPassword, err := beforeConnect(cfg)
+	if err != nil {
+		return err
+	}
+	if setPassword != nil {
+		if err := setPassword(ctx, connConfig); err != nil {
+			return err
+		}
+	}
+	conn, err := pgx.ConnectConfig(ctx, connConfig)
 	if err != nil {
 		return fmt.Errorf("データベース接続失敗: %w", err)
 	}
@@ -50,6 +131,6 @@ func TestConnection() error {
 		return fmt.Errorf("データベースクエリ結果が期待値と異なります: expected 1, got %d", result)
 	}
 
-	fmt.Printf("✅ データベース疎通確認成功\n")
+	slog.Info("データベース疎通確認成功")
 	return nil
 }
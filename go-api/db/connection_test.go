@@ -0,0 +1,181 @@
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/config"
+)
+
+// writeTestCerts テスト用のCA証明書とクライアント証明書・秘密鍵を dir に作成し、パスを返す
+func writeTestCerts(t *testing.T, dir string) (rootCert, cert, key string) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "app_user"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caTemplate, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	return write("root.crt", "CERTIFICATE", caDER),
+		write("client.crt", "CERTIFICATE", clientDER),
+		write("client.key", "PRIVATE KEY", keyDER)
+}
+
+func TestDatabaseDSNTLS(t *testing.T) {
+	// パスに空白や引用符を含んでも接続文字列が壊れないことも確認する
+	dir := filepath.Join(t.TempDir(), "it's certs")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	rootCert, cert, key := writeTestCerts(t, dir)
+
+	tests := []struct {
+		name           string
+		db             config.DBConfig
+		wantTLS        bool
+		wantServerName string
+		wantRootCAs    bool
+		wantClientCert bool
+		wantVerifyPeer bool
+	}{
+		{
+			name: "disable",
+			db:   config.DBConfig{Host: "db.example.com", SSLMode: "disable"},
+		},
+		{
+			name:           "verify-full with CA",
+			db:             config.DBConfig{Host: "db.example.com", SSLMode: "verify-full", SSLRootCert: rootCert},
+			wantTLS:        true,
+			wantServerName: "db.example.com",
+			wantRootCAs:    true,
+		},
+		{
+			name:           "verify-full with CA and client cert",
+			db:             config.DBConfig{Host: "db.example.com", SSLMode: "verify-full", SSLRootCert: rootCert, SSLCert: cert, SSLKey: key},
+			wantTLS:        true,
+			wantServerName: "db.example.com",
+			wantRootCAs:    true,
+			wantClientCert: true,
+		},
+		{
+			name:           "verify-ca does not check host name",
+			db:             config.DBConfig{Host: "10.0.0.5", SSLMode: "verify-ca", SSLRootCert: rootCert},
+			wantTLS:        true,
+			wantRootCAs:    true,
+			wantVerifyPeer: true,
+		},
+		{
+			name:           "require with CA verifies like verify-ca",
+			db:             config.DBConfig{Host: "10.0.0.5", SSLMode: "require", SSLRootCert: rootCert},
+			wantTLS:        true,
+			wantRootCAs:    true,
+			wantVerifyPeer: true,
+		},
+		{
+			name: "unix socket ignores TLS settings",
+			db:   config.DBConfig{Host: "/cloudsql/project:asia-northeast1:instance", SSLMode: "verify-full", SSLRootCert: rootCert, SSLCert: cert, SSLKey: key},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.db.Port = 5432
+			tt.db.User = "app_user"
+			tt.db.Database = "cookorder"
+			cfg := &config.Config{DB: tt.db}
+
+			connConfig, err := pgx.ParseConfig(cfg.GetDatabaseDSN())
+			if err != nil {
+				t.Fatalf("ParseConfig(%q): %v", cfg.GetDatabaseDSN(), err)
+			}
+			if connConfig.Host != tt.db.Host || connConfig.User != "app_user" || connConfig.Database != "cookorder" {
+				t.Errorf("host, user, database = %q, %q, %q", connConfig.Host, connConfig.User, connConfig.Database)
+			}
+
+			tlsConfig := connConfig.TLSConfig
+			if (tlsConfig != nil) != tt.wantTLS {
+				t.Fatalf("TLSConfig = %v, want TLS %v", tlsConfig, tt.wantTLS)
+			}
+			if tlsConfig == nil {
+				return
+			}
+			if tlsConfig.ServerName != tt.wantServerName {
+				t.Errorf("ServerName = %q, want %q", tlsConfig.ServerName, tt.wantServerName)
+			}
+			if (tlsConfig.RootCAs != nil) != tt.wantRootCAs {
+				t.Errorf("RootCAs set = %v, want %v", tlsConfig.RootCAs != nil, tt.wantRootCAs)
+			}
+			if (len(tlsConfig.Certificates) == 1) != tt.wantClientCert {
+				t.Errorf("client certificates = %d, want client cert %v", len(tlsConfig.Certificates), tt.wantClientCert)
+			}
+			if (tlsConfig.VerifyPeerCertificate != nil) != tt.wantVerifyPeer {
+				t.Errorf("VerifyPeerCertificate set = %v, want %v", tlsConfig.VerifyPeerCertificate != nil, tt.wantVerifyPeer)
+			}
+		})
+	}
+}
+
+// TestTestConnection 実際のデータベースに PG_* の設定で接続する（PG_INTEGRATION_TEST が設定されている場合のみ）
+// TLS（verify-full とクライアント証明書）や Unix ソケットの接続を確認する場合は、.env.example の手順でデータベースを起動する
+func TestTestConnection(t *testing.T) {
+	if os.Getenv("PG_INTEGRATION_TEST") == "" {
+		t.Skip("PG_INTEGRATION_TEST is not set")
+	}
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	if err := TestConnection(); err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID マイグレーションの同時実行を防ぐためのアドバイザリロックID
+const migrationLockID = 7_204_310_001
+
+// Migrate 未適用のマイグレーションを番号順に適用する
+func Migrate(ctx context.Context) error {
+	conn, err := ConnectDB()
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// 複数インスタンスが同時に起動してもマイグレーションが重複しないようにロックを取得
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("マイグレーションロック取得失敗: %w", err)
+	}
+	defer conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("schema_migrations作成失敗: %w", err)
+	}
+
+	versions, err := migrationVersions()
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		var applied bool
+		err := conn.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("マイグレーション状態の取得失敗: %w", err)
+		}
+		if applied {
+			continue
+		}
+
+		sql, err := migrationFiles.ReadFile(path.Join("migrations", version+".sql"))
+		if err != nil {
+			return fmt.Errorf("マイグレーションファイル読み込み失敗 (%s): %w", version, err)
+		}
+
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("トランザクション開始失敗: %w", err)
+		}
+		if _, err := tx.Exec(ctx, string(sql)); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("マイグレーション適用失敗 (%s): %w", version, err)
+		}
+		if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("マイグレーション記録失敗 (%s): %w", version, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("マイグレーションコミット失敗 (%s): %w", version, err)
+		}
+
+		slog.InfoContext(ctx, "マイグレーション適用", "version", version)
+	}
+
+	return nil
+}
+
+// PendingMigrations 未適用のマイグレーションのバージョン一覧を返す（ヘルスチェック用）
+func PendingMigrations(ctx context.Context) ([]string, error) {
+	conn, err := acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーション状態の取得失敗: %w", err)
+	}
+	applied, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーション状態の取得失敗: %w", err)
+	}
+
+	versions, err := migrationVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []string{}
+	for _, version := range versions {
+		if !slices.Contains(applied, version) {
+			pending = append(pending, version)
+		}
+	}
+	return pending, nil
+}
+
+// migrationVersions 埋め込まれたマイグレーションのバージョン一覧を番号順に返す
+func migrationVersions() ([]string, error) {
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーション一覧取得失敗: %w", err)
+	}
+
+	versions := make([]string, 0, len(names))
+	for _, name := range names {
+		versions = append(versions, strings.TrimSuffix(path.Base(name), ".sql"))
+	}
+	sort.Strings(versions)
+
+	return versions, nil
+}
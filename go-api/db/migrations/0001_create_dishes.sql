@@ -0,0 +1,8 @@
+-- 既存環境ではテーブルが作成済みのため IF NOT EXISTS で作成する
+CREATE TABLE IF NOT EXISTS dishes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name_ja TEXT NOT NULL,
+    name_en TEXT NOT NULL,
+    price INTEGER NOT NULL,
+    photo_url TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,28 @@
+-- 料理のオプショングループ（サイズ、辛さ、トッピングなど）
+CREATE TABLE modifier_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dish_id UUID NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    name_ja TEXT NOT NULL,
+    name_en TEXT NOT NULL,
+    selection_type TEXT NOT NULL CHECK (selection_type IN ('single', 'multi')),
+    min_choices INTEGER NOT NULL DEFAULT 0,
+    max_choices INTEGER NOT NULL DEFAULT 1,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (min_choices >= 0 AND max_choices >= min_choices)
+);
+
+CREATE INDEX modifier_groups_dish_id_idx ON modifier_groups (dish_id);
+
+-- オプショングループ内の選択肢
+CREATE TABLE modifier_options (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES modifier_groups (id) ON DELETE CASCADE,
+    name_ja TEXT NOT NULL,
+    name_en TEXT NOT NULL,
+    price_delta INTEGER NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX modifier_options_group_id_idx ON modifier_options (group_id);
@@ -0,0 +1,29 @@
+-- セットメニュー（メイン・サイド・ドリンクなどの組み合わせ）
+CREATE TABLE bundles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name_ja TEXT NOT NULL,
+    name_en TEXT NOT NULL,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    photo_url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- セットを構成する枠（メイン、サイド、ドリンクなど）
+CREATE TABLE bundle_slots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bundle_id UUID NOT NULL REFERENCES bundles (id) ON DELETE CASCADE,
+    name_ja TEXT NOT NULL,
+    name_en TEXT NOT NULL,
+    default_dish_id UUID NOT NULL REFERENCES dishes (id),
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX bundle_slots_bundle_id_idx ON bundle_slots (bundle_id);
+
+-- 枠ごとに選択可能な料理（差し替え候補）と差額
+CREATE TABLE bundle_slot_choices (
+    slot_id UUID NOT NULL REFERENCES bundle_slots (id) ON DELETE CASCADE,
+    dish_id UUID NOT NULL REFERENCES dishes (id),
+    price_delta INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (slot_id, dish_id)
+);
@@ -0,0 +1,31 @@
+-- 料理カテゴリ（カテゴリ単位の価格ルールに使用）
+ALTER TABLE dishes ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+-- 予約された価格変更（effective_at 以降に適用される）
+CREATE TABLE price_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dish_id UUID NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    price INTEGER NOT NULL CHECK (price >= 1),
+    effective_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX price_changes_dish_id_effective_at_idx ON price_changes (dish_id, effective_at DESC);
+
+-- 曜日・時間帯で繰り返し適用される価格ルール（ハッピーアワーなど）
+CREATE TABLE price_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    dish_id UUID REFERENCES dishes (id) ON DELETE CASCADE,
+    category TEXT,
+    days_of_week INTEGER[] NOT NULL DEFAULT '{}',
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    starts_on DATE,
+    ends_on DATE,
+    adjustment_type TEXT NOT NULL CHECK (adjustment_type IN ('fixed', 'percent', 'amount')),
+    value INTEGER NOT NULL CHECK (value >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((dish_id IS NULL) <> (category IS NULL))
+);
@@ -0,0 +1,23 @@
+-- 料理の変更履歴（追記のみ、料理を削除しても残すため外部キーは張らない）
+CREATE TABLE dish_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dish_id UUID NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    old_values JSONB,
+    new_values JSONB,
+    actor TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX dish_revisions_dish_id_created_at_idx ON dish_revisions (dish_id, created_at DESC);
+
+-- 履歴の更新・削除を禁止する
+CREATE FUNCTION dish_revisions_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'dish_revisions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER dish_revisions_append_only
+    BEFORE UPDATE OR DELETE ON dish_revisions
+    FOR EACH ROW EXECUTE FUNCTION dish_revisions_append_only();
@@ -0,0 +1,4 @@
+-- 論理削除（ゴミ箱）用の削除日時
+ALTER TABLE dishes ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX dishes_deleted_at_idx ON dishes (deleted_at) WHERE deleted_at IS NOT NULL;
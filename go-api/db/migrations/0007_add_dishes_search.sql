@@ -0,0 +1,14 @@
+-- 日本語検索用のふりがなと検索対象文字列
+-- search_text は search.Document と同じ規則（NFKC・小文字・カタカナをひらがなに統一）で作成する
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE dishes ADD COLUMN reading TEXT NOT NULL DEFAULT '';
+ALTER TABLE dishes ADD COLUMN search_text TEXT NOT NULL DEFAULT '';
+
+-- 既存データの検索対象文字列を作成
+UPDATE dishes SET search_text = btrim(regexp_replace(
+    translate(lower(normalize(name_ja, NFKC)), 'ァアィイゥウェエォオカガキギクグケゲコゴサザシジスズセゼソゾタダチヂッツヅテデトドナニヌネノハバパヒビピフブプヘベペホボポマミムメモャヤュユョヨラリルレロヮワヰヱヲンヴヵヶヽヾ', 'ぁあぃいぅうぇえぉおかがきぎくぐけげこごさざしじすずせぜそぞただちぢっつづてでとどなにぬねのはばぱひびぴふぶぷへべぺほぼぽまみむめもゃやゅゆょよらりるれろゎわゐゑをんゔゕゖゝゞ') || ' ' || translate(lower(normalize(name_en, NFKC)), 'ァアィイゥウェエォオカガキギクグケゲコゴサザシジスズセゼソゾタダチヂッツヅテデトドナニヌネノハバパヒビピフブプヘベペホボポマミムメモャヤュユョヨラリルレロヮワヰヱヲンヴヵヶヽヾ', 'ぁあぃいぅうぇえぉおかがきぎくぐけげこごさざしじすずせぜそぞただちぢっつづてでとどなにぬねのはばぱひびぴふぶぷへべぺほぼぽまみむめもゃやゅゆょよらりるれろゎわゐゑをんゔゕゖゝゞ'),
+    '\s+', ' ', 'g'
+));
+
+CREATE INDEX dishes_search_text_trgm_idx ON dishes USING GIN (search_text gin_trgm_ops);
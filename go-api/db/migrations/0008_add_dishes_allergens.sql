@@ -0,0 +1,4 @@
+-- 料理に含まれるアレルゲン（model.Allergen* のコード）
+ALTER TABLE dishes ADD COLUMN allergens TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX dishes_allergens_idx ON dishes USING GIN (allergens);
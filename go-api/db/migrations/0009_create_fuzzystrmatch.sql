@@ -0,0 +1,2 @@
+-- 英語名のタイプミス検索・「もしかして」候補に使う編集距離（levenshtein）
+CREATE EXTENSION IF NOT EXISTS fuzzystrmatch;
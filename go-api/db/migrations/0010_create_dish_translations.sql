@@ -0,0 +1,9 @@
+-- 料理名・説明の翻訳（日本語・英語は dishes の name_ja・name_en を使う）
+CREATE TABLE dish_translations (
+    dish_id UUID NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (dish_id, locale)
+);
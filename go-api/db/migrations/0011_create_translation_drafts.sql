@@ -0,0 +1,19 @@
+-- 翻訳の用語集（日本語の用語と各ロケールの訳語、辞書翻訳で使う）
+CREATE TABLE translation_glossary (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    term TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    translation TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (term, locale)
+);
+
+-- 機械翻訳で作成した料理名の下書き（承認すると dishes.name_en または dish_translations に反映する）
+CREATE TABLE dish_translation_drafts (
+    dish_id UUID NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (dish_id, locale)
+);
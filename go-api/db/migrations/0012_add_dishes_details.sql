@@ -0,0 +1,12 @@
+-- 料理の詳細情報（説明・原材料・栄養成分・辛さ・量）
+-- 日本語・英語以外の説明は dish_translations.description に保存する
+ALTER TABLE dishes
+    ADD COLUMN description_ja TEXT NOT NULL DEFAULT '',
+    ADD COLUMN description_en TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ingredients TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN calories INTEGER CHECK (calories >= 0),
+    ADD COLUMN protein NUMERIC(6, 1) CHECK (protein >= 0),
+    ADD COLUMN fat NUMERIC(6, 1) CHECK (fat >= 0),
+    ADD COLUMN carbohydrate NUMERIC(6, 1) CHECK (carbohydrate >= 0),
+    ADD COLUMN spice_level SMALLINT NOT NULL DEFAULT 0 CHECK (spice_level BETWEEN 0 AND 5),
+    ADD COLUMN portion_size TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,19 @@
+-- 料理の写真（ギャラリー）。代表写真は dishes.photo_url と同じ写真
+CREATE TABLE dish_photos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dish_id UUID NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    object_name TEXT NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_cover BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (dish_id, object_name)
+);
+
+CREATE INDEX dish_photos_dish_id_sort_order_idx ON dish_photos (dish_id, sort_order);
+
+-- 代表写真は料理ごとに1枚
+CREATE UNIQUE INDEX dish_photos_cover_idx ON dish_photos (dish_id) WHERE is_cover;
+
+-- 既存の写真を代表写真として登録する
+INSERT INTO dish_photos (dish_id, object_name, sort_order, is_cover)
+SELECT id, photo_url, 0, true FROM dishes WHERE photo_url <> '';
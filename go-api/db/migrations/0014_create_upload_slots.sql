@@ -0,0 +1,11 @@
+-- 署名付きURLで直接アップロードするための写真のアップロード枠
+-- 料理の作成・更新で使われると削除し、期限切れの枠はアップロードされたファイルとともに削除する
+CREATE TABLE upload_slots (
+    object_name TEXT PRIMARY KEY,
+    content_type TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX upload_slots_expires_at_idx ON upload_slots (expires_at);
@@ -0,0 +1,4 @@
+-- 写真の公開設定。非公開の写真（スタッフの顔写真など）はハッシュ名で保存せず、常に署名付きURLで配信する
+-- 代表写真は料理の一覧などで表示するため公開の写真に限る
+ALTER TABLE dish_photos ADD COLUMN is_public BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE dish_photos ADD CONSTRAINT dish_photos_cover_public_check CHECK (is_public OR NOT is_cover);
@@ -0,0 +1,3 @@
+-- アップロード枠の発行時に申告されたファイルサイズ。署名付きURLとアップロード後の確認でこのサイズちょうどかを確認する
+-- サイズを記録する前に発行された枠は NULL（上限の確認のみ）
+ALTER TABLE upload_slots ADD COLUMN size BIGINT CHECK (size > 0);
@@ -0,0 +1,27 @@
+-- 検索対象文字列（search_text）を空白で区切った語。タイプミス検索と「もしかして」候補の語を
+-- トライグラムインデックスで絞り込んでから編集距離で判定するために使う
+CREATE TABLE dish_search_words (
+    dish_id UUID NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    word TEXT NOT NULL,
+    PRIMARY KEY (dish_id, word)
+);
+
+CREATE INDEX dish_search_words_word_trgm_idx ON dish_search_words USING GIN (word gin_trgm_ops);
+
+-- search_text の変更に合わせて語を作り直す
+CREATE FUNCTION dish_search_words_sync() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM dish_search_words WHERE dish_id = NEW.id;
+    INSERT INTO dish_search_words (dish_id, word)
+    SELECT DISTINCT NEW.id, w FROM regexp_split_to_table(NEW.search_text, ' ') AS w WHERE w <> '';
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER dish_search_words_sync
+    AFTER INSERT OR UPDATE OF search_text ON dishes
+    FOR EACH ROW EXECUTE FUNCTION dish_search_words_sync();
+
+-- 既存の料理の語を登録する
+INSERT INTO dish_search_words (dish_id, word)
+SELECT DISTINCT id, w FROM dishes, regexp_split_to_table(search_text, ' ') AS w WHERE w <> '';
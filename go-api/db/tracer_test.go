@@ -0,0 +1,32 @@
+package db
+
+import "testing"
+
+func TestSQLOperation(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT 1", "SELECT"},
+		{"select id from dishes", "SELECT"},
+		{"\n\t  INSERT INTO dishes (name_ja) VALUES ($1)", "INSERT"},
+		{"update dishes set price = $1", "UPDATE"},
+		{"DELETE FROM dishes WHERE id = $1", "DELETE"},
+		{"WITH moved AS (SELECT 1) SELECT * FROM moved", "WITH"},
+		{"begin", "BEGIN"},
+		{"COMMIT", "COMMIT"},
+		{"ROLLBACK", "ROLLBACK"},
+		{"CREATE TABLE t (id int)", "CREATE"},
+		{"ALTER TABLE t ADD COLUMN c int", "ALTER"},
+		{"DROP TABLE t", "DROP"},
+		{"SAVEPOINT suggest_translations", "OTHER"},
+		{"LOCK TABLE dishes", "OTHER"},
+		{"", "OTHER"},
+		{"  \n ", "OTHER"},
+	}
+	for _, tt := range tests {
+		if got := sqlOperation(tt.sql); got != tt.want {
+			t.Errorf("sqlOperation(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,679 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/metrics"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/pricing"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// BundleQuoteRequest セット価格算出のリクエスト構造体
+type BundleQuoteRequest struct {
+	// Selections 枠IDから選択した料理IDへのマップ（指定のない枠は既定の料理）
+	Selections map[string]string `json:"selections"`
+}
+
+// セットメニュー作成ハンドラー
+// @Summary セットメニュー作成
+// @Description 新しいセットメニューを登録します（写真ファイルとセット情報を同時に送信）
+// @Tags bundles
+// @Accept multipart/form-data
+// @Produce json
+// @Param photo formData file true "セットの写真ファイル"
+// @Param nameJa formData string true "セット名（日本語）"
+// @Param nameEn formData string true "セット名（英語）"
+// @Param price formData integer true "セット価格"
+// @Param slots formData string true "構成枠（BundleSlotRequestの配列をJSON文字列で指定）"
+// @Success 201 {object} model.Bundle
+// @Failure 400 {object} ErrorResponse
+// @Router /bundles [post]
+func PostBundle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "フォーム", "フォームデータの解析に失敗しました")
+		return
+	}
+
+	req := BundleRequest{
+		NameJa: r.FormValue("nameJa"),
+		NameEn: r.FormValue("nameEn"),
+	}
+	if p, err := strconv.Atoi(r.FormValue("price")); err == nil {
+		req.Price = p
+	}
+	if slots := r.FormValue("slots"); slots != "" {
+		if err := json.Unmarshal([]byte(slots), &req.Slots); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "構成枠", "構成枠のJSONの解析に失敗しました")
+			return
+		}
+	}
+
+	if validationErrors := validateBundleRequest(&req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return
+	}
+
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", "写真ファイルが選択されていません")
+		return
+	}
+	defer file.Close()
+
+	if !isValidImageFormat(header.Filename) {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	if ok, err := bundleDishesExist(r.Context(), conn, req.Slots); err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	} else if !ok {
+		writeErrorResponse(w, http.StatusBadRequest, "構成枠", "存在しない料理が指定されています")
+		return
+	}
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	photoURL, err := uploadPhoto(r.Context(), gcsClient, file, header, true)
+	if err != nil {
+		writeServerError(w, r, err, "写真", "写真のアップロードに失敗しました")
+		return
+	}
+	// 登録に失敗した場合はアップロードした写真を削除する
+	committed := false
+	defer func() {
+		if !committed {
+			discardPhoto(r.Context(), gcsClient, photoURL)
+		}
+	}()
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	var id string
+	err = tx.QueryRow(r.Context(),
+		`INSERT INTO bundles (name_ja, name_en, price, photo_url) VALUES ($1, $2, $3, $4) RETURNING id`,
+		req.NameJa, req.NameEn, req.Price, photoURL,
+	).Scan(&id)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "セットメニューの登録に失敗しました")
+		return
+	}
+
+	if err := insertBundleSlots(r.Context(), tx, id, req.Slots); err != nil {
+		writeServerError(w, r, err, "データベース", "構成枠の登録に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "セットメニューの登録に失敗しました")
+		return
+	}
+	committed = true
+	metrics.BundlesCreated.Inc()
+
+	writeBundle(w, r, conn, gcsClient, id, time.Now(), http.StatusCreated)
+}
+
+// セットメニュー一覧取得ハンドラー
+// @Summary セットメニュー一覧取得
+// @Description セットメニュー一覧を構成枠付きで取得します
+// @Tags bundles
+// @Produce json
+// @Param at query string false "構成品の価格を判定する日時（RFC3339、省略時は現在日時）"
+// @Success 200 {array} model.Bundle
+// @Failure 400 {object} ErrorResponse
+// @Router /bundles [get]
+func AdminGetBundles(w http.ResponseWriter, r *http.Request) {
+	at, err := parsePriceAt(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "日時", "at はRFC3339形式で指定してください")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	rows, err := conn.Query(r.Context(),
+		`SELECT id, name_ja, name_en, price, photo_url FROM bundles ORDER BY created_at`)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "セットメニューの取得に失敗しました")
+		return
+	}
+
+	bundles := []model.Bundle{}
+	for rows.Next() {
+		var b model.Bundle
+		if err := rows.Scan(&b.ID, &b.NameJa, &b.NameEn, &b.Price, &b.Img); err != nil {
+			rows.Close()
+			writeServerError(w, r, err, "データベース", "セットメニューの取得に失敗しました")
+			return
+		}
+		bundles = append(bundles, b)
+	}
+	rows.Close()
+
+	resolver, err := loadPriceResolver(r.Context(), conn, at)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+	for i := range bundles {
+		bundles[i].Slots, err = loadBundleSlots(r.Context(), conn, bundles[i].ID, resolver, at)
+		if err != nil {
+			writeServerError(w, r, err, "データベース", "構成枠の取得に失敗しました")
+			return
+		}
+	}
+
+	imgs := make([]*string, len(bundles))
+	for i := range bundles {
+		imgs[i] = &bundles[i].Img
+	}
+	if err := signImageURLs(r.Context(), gcsClient, imgs); err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bundles)
+}
+
+// セットメニュー詳細取得ハンドラー
+// @Summary セットメニュー詳細取得
+// @Description ID指定でセットメニューの詳細を取得します
+// @Tags bundles
+// @Produce json
+// @Param id path string true "セットID"
+// @Param at query string false "構成品の価格を判定する日時（RFC3339、省略時は現在日時）"
+// @Success 200 {object} model.Bundle
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /bundles/{id} [get]
+func AdminGetBundle(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	at, err := parsePriceAt(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "日時", "at はRFC3339形式で指定してください")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	writeBundle(w, r, conn, gcsClient, id, at, http.StatusOK)
+}
+
+// セットメニュー更新ハンドラー
+// @Summary セットメニュー更新
+// @Description ID指定でセットメニューを更新します（指定した項目のみ更新、構成枠は指定時に全件置き換え）
+// @Tags bundles
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "セットID"
+// @Param photo formData file false "セットの写真ファイル（変更する場合のみ）"
+// @Param nameJa formData string false "セット名（日本語）"
+// @Param nameEn formData string false "セット名（英語）"
+// @Param price formData integer false "セット価格"
+// @Param slots formData string false "構成枠（BundleSlotRequestの配列をJSON文字列で指定）"
+// @Success 200 {object} model.Bundle
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /bundles/{id} [put]
+func PutBundle(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "フォーム", "フォームデータの解析に失敗しました")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	current, err := loadBundle(r.Context(), conn, id, time.Now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "セットメニュー", "指定されたIDのセットメニューが見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "セットメニューの取得に失敗しました")
+		return
+	}
+
+	// 現在の値で初期化し、提供された値で上書き
+	req := bundleToRequest(current)
+	if nameJa := r.FormValue("nameJa"); nameJa != "" {
+		req.NameJa = nameJa
+	}
+	if nameEn := r.FormValue("nameEn"); nameEn != "" {
+		req.NameEn = nameEn
+	}
+	if priceStr := r.FormValue("price"); priceStr != "" {
+		price, err := strconv.Atoi(priceStr)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "価格", "価格が不正です")
+			return
+		}
+		req.Price = price
+	}
+	replaceSlots := r.FormValue("slots") != ""
+	if replaceSlots {
+		req.Slots = nil
+		if err := json.Unmarshal([]byte(r.FormValue("slots")), &req.Slots); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "構成枠", "構成枠のJSONの解析に失敗しました")
+			return
+		}
+	}
+
+	if validationErrors := validateBundleRequest(&req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return
+	}
+
+	if replaceSlots {
+		if ok, err := bundleDishesExist(r.Context(), conn, req.Slots); err != nil {
+			writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+			return
+		} else if !ok {
+			writeErrorResponse(w, http.StatusBadRequest, "構成枠", "存在しない料理が指定されています")
+			return
+		}
+	}
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	// 写真ファイルの処理（オプショナル）
+	photoURL := current.Img
+	file, header, err := r.FormFile("photo")
+	if err == nil {
+		defer file.Close()
+
+		if !isValidImageFormat(header.Filename) {
+			writeErrorResponse(w, http.StatusBadRequest, "写真", "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています")
+			return
+		}
+
+		photoURL, err = uploadPhoto(r.Context(), gcsClient, file, header, true)
+		if err != nil {
+			writeServerError(w, r, err, "写真", "写真のアップロードに失敗しました")
+			return
+		}
+	}
+	// 更新に失敗した場合は新しくアップロードした写真を削除する
+	committed := false
+	defer func() {
+		if !committed && photoURL != current.Img {
+			discardPhoto(r.Context(), gcsClient, photoURL)
+		}
+	}()
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	_, err = tx.Exec(r.Context(),
+		`UPDATE bundles SET name_ja = $1, name_en = $2, price = $3, photo_url = $4 WHERE id = $5`,
+		req.NameJa, req.NameEn, req.Price, photoURL, id,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "更新に失敗しました")
+		return
+	}
+
+	if replaceSlots {
+		if _, err := tx.Exec(r.Context(), `DELETE FROM bundle_slots WHERE bundle_id = $1`, id); err != nil {
+			writeServerError(w, r, err, "データベース", "構成枠の更新に失敗しました")
+			return
+		}
+		if err := insertBundleSlots(r.Context(), tx, id, req.Slots); err != nil {
+			writeServerError(w, r, err, "データベース", "構成枠の更新に失敗しました")
+			return
+		}
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "更新に失敗しました")
+		return
+	}
+	committed = true
+
+	writeBundle(w, r, conn, gcsClient, id, time.Now(), http.StatusOK)
+}
+
+// セットメニュー削除ハンドラー
+// @Summary セットメニュー削除
+// @Description ID指定でセットメニューを削除します
+// @Tags bundles
+// @Param id path string true "セットID"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /bundles/{id} [delete]
+func DeleteBundle(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	result, err := conn.Exec(r.Context(), `DELETE FROM bundles WHERE id = $1`, id)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "削除に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "セットメニュー", "指定されたIDのセットメニューが見つかりません")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// セット価格算出ハンドラー
+// @Summary セット価格算出
+// @Description 選択した構成品からセット価格を算出します
+// @Tags bundles
+// @Accept json
+// @Produce json
+// @Param id path string true "セットID"
+// @Param at query string false "構成品の価格を判定する日時（RFC3339、省略時は現在日時）"
+// @Param body body BundleQuoteRequest true "構成品の選択"
+// @Success 200 {object} pricing.BundleQuote
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /bundles/{id}/quote [post]
+func QuoteBundle(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	at, err := parsePriceAt(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "日時", "at はRFC3339形式で指定してください")
+		return
+	}
+
+	var req BundleQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	bundle, err := loadBundle(r.Context(), conn, id, at)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "セットメニュー", "指定されたIDのセットメニューが見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "セットメニューの取得に失敗しました")
+		return
+	}
+
+	quote, err := pricing.ResolveBundlePrice(bundle, req.Selections)
+	if err != nil {
+		switch {
+		case errors.Is(err, pricing.ErrUnknownSlot):
+			writeErrorResponse(w, http.StatusBadRequest, "構成枠", "セットに存在しない構成枠が指定されています")
+		case errors.Is(err, pricing.ErrSubstitutionNotAllowed):
+			writeErrorResponse(w, http.StatusBadRequest, "構成枠", "この構成枠では選択できない料理が指定されています")
+		case errors.Is(err, pricing.ErrDefaultDishUnavailable):
+			writeErrorResponse(w, http.StatusConflict, "構成枠", "構成枠の既定の料理が提供できません（削除済みなど）。料理を選択するか、セットメニューを更新してください")
+		default:
+			writeServerError(w, r, err, "価格", "セット価格の算出に失敗しました")
+		}
+		return
+	}
+
+	metrics.BundleQuotes.Inc()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+// writeBundle セットメニューを取得し、画像URLを署名付きURLに変換してレスポンスに書き込む
+// 構成品の価格は at 時点の実売価格にする
+func writeBundle(w http.ResponseWriter, r *http.Request, q db.Querier, gcsClient *utils.GCSClient, id string, at time.Time, statusCode int) {
+	bundle, err := loadBundle(r.Context(), q, id, at)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "セットメニュー", "指定されたIDのセットメニューが見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "セットメニューの取得に失敗しました")
+		return
+	}
+
+	bundle.Img, err = signImageURL(r.Context(), gcsClient, bundle.Img)
+	if err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(bundle)
+}
+
+// loadBundle セットメニューを構成枠付きで取得する（存在しない場合は pgx.ErrNoRows）
+// 選択肢の料理の価格は at 時点の実売価格にする
+func loadBundle(ctx context.Context, q db.Querier, id string, at time.Time) (model.Bundle, error) {
+	var b model.Bundle
+	err := q.QueryRow(ctx,
+		`SELECT id, name_ja, name_en, price, photo_url FROM bundles WHERE id = $1`, id,
+	).Scan(&b.ID, &b.NameJa, &b.NameEn, &b.Price, &b.Img)
+	if err != nil {
+		return model.Bundle{}, err
+	}
+
+	resolver, err := loadPriceResolver(ctx, q, at)
+	if err != nil {
+		return model.Bundle{}, err
+	}
+	b.Slots, err = loadBundleSlots(ctx, q, b.ID, resolver, at)
+	if err != nil {
+		return model.Bundle{}, err
+	}
+
+	return b, nil
+}
+
+// loadBundleSlots セットの構成枠を選択肢付きで取得する
+// 選択肢の料理の価格は料理一覧と同じく、価格変更と価格ルールを反映した at 時点の実売価格にする
+func loadBundleSlots(ctx context.Context, q db.Querier, bundleID string, resolver *pricing.Resolver, at time.Time) ([]model.BundleSlot, error) {
+	rows, err := q.Query(ctx,
+		`SELECT id, name_ja, name_en, default_dish_id, sort_order
+		 FROM bundle_slots WHERE bundle_id = $1 ORDER BY sort_order, name_ja`,
+		bundleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []model.BundleSlot{}
+	index := map[string]int{}
+	for rows.Next() {
+		var s model.BundleSlot
+		if err := rows.Scan(&s.ID, &s.NameJa, &s.NameEn, &s.DefaultDishID, &s.SortOrder); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		s.Choices = []model.BundleSlotChoice{}
+		index[s.ID] = len(slots)
+		slots = append(slots, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.Query(ctx,
+		`SELECT c.slot_id, c.dish_id, d.name_ja, d.name_en, d.category, d.price, c.price_delta
+		 FROM bundle_slot_choices c
+		 JOIN bundle_slots s ON s.id = c.slot_id
+		 JOIN dishes d ON d.id = c.dish_id
+		 WHERE s.bundle_id = $1 AND d.deleted_at IS NULL
+		 ORDER BY c.price_delta, d.name_ja`,
+		bundleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c model.BundleSlotChoice
+		var slotID, category string
+		if err := rows.Scan(&slotID, &c.DishID, &c.NameJa, &c.NameEn, &category, &c.DishPrice, &c.PriceDelta); err != nil {
+			return nil, err
+		}
+		c.DishPrice = resolver.Resolve(c.DishID, category, c.DishPrice, at).Price
+		if i, ok := index[slotID]; ok {
+			slots[i].Choices = append(slots[i].Choices, c)
+		}
+	}
+
+	return slots, rows.Err()
+}
+
+// insertBundleSlots 構成枠と選択肢をまとめて登録する
+func insertBundleSlots(ctx context.Context, q db.Querier, bundleID string, slots []BundleSlotRequest) error {
+	for _, slot := range slots {
+		var slotID string
+		err := q.QueryRow(ctx,
+			`INSERT INTO bundle_slots (bundle_id, name_ja, name_en, default_dish_id, sort_order)
+			 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			bundleID, slot.NameJa, slot.NameEn, slot.DefaultDishID, slot.SortOrder,
+		).Scan(&slotID)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range slot.Choices {
+			_, err := q.Exec(ctx,
+				`INSERT INTO bundle_slot_choices (slot_id, dish_id, price_delta) VALUES ($1, $2, $3)`,
+				slotID, c.DishID, c.PriceDelta,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bundleDishesExist 構成枠で指定された料理がすべて存在するか確認する
+func bundleDishesExist(ctx context.Context, q db.Querier, slots []BundleSlotRequest) (bool, error) {
+	ids := map[string]bool{}
+	for _, slot := range slots {
+		ids[slot.DefaultDishID] = true
+		for _, c := range slot.Choices {
+			ids[c.DishID] = true
+		}
+	}
+
+	dishIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		dishIDs = append(dishIDs, id)
+	}
+
+	var count int
+	err := q.QueryRow(ctx, `SELECT count(*) FROM dishes WHERE id::text = ANY($1) AND deleted_at IS NULL`, dishIDs).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count == len(dishIDs), nil
+}
+
+// bundleToRequest 既存のセットメニューを更新用のリクエスト構造体に変換する
+func bundleToRequest(b model.Bundle) BundleRequest {
+	req := BundleRequest{
+		NameJa: b.NameJa,
+		NameEn: b.NameEn,
+		Price:  b.Price,
+		Slots:  make([]BundleSlotRequest, 0, len(b.Slots)),
+	}
+	for _, s := range b.Slots {
+		slot := BundleSlotRequest{
+			NameJa:        s.NameJa,
+			NameEn:        s.NameEn,
+			DefaultDishID: s.DefaultDishID,
+			SortOrder:     s.SortOrder,
+		}
+		for _, c := range s.Choices {
+			slot.Choices = append(slot.Choices, BundleChoiceRequest{DishID: c.DishID, PriceDelta: c.PriceDelta})
+		}
+		req.Slots = append(req.Slots, slot)
+	}
+	return req
+}
@@ -3,11 +3,13 @@ package admin
 import (
 	"context"
 	"encoding/json"
-	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/metrics"
 	"github.com/smilemasa/go-api/model"
 	"github.com/smilemasa/go-api/utils"
 )
@@ -18,10 +20,23 @@ import (
 // @Tags dishes
 // @Accept multipart/form-data
 // @Produce json
-// @Param photo formData file true "料理の写真ファイル"
+// @Param photo formData file false "料理の写真ファイル（photoKey を指定しない場合は必須）"
+// @Param photoKey formData string false "直接アップロードした写真のキー（/uploads で発行）"
 // @Param nameJa formData string true "料理名（日本語）"
-// @Param nameEn formData string true "料理名（英語）"
+// @Param nameEn formData string false "料理名（英語、省略時は日本語名を機械翻訳した下書きを作成）"
+// @Param reading formData string false "ふりがな（ひらがな・カタカナ、検索に使用）"
 // @Param price formData integer true "料理の価格"
+// @Param category formData string false "カテゴリ"
+// @Param allergens formData string false "アレルゲン（カンマ区切り、例: egg,milk）"
+// @Param descriptionJa formData string false "説明（日本語）"
+// @Param descriptionEn formData string false "説明（英語）"
+// @Param ingredients formData string false "原材料（カンマ・読点・改行区切り）"
+// @Param calories formData integer false "エネルギー（kcal）"
+// @Param protein formData number false "たんぱく質（g）"
+// @Param fat formData number false "脂質（g）"
+// @Param carbohydrate formData number false "炭水化物（g）"
+// @Param spiceLevel formData integer false "辛さ（0〜5）"
+// @Param portionSize formData string false "量（例: 1人前 約300g）"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Router /dishes [post]
@@ -31,8 +46,12 @@ func PostDish(w http.ResponseWriter, r *http.Request) {
 
 	// Get form data for dish information first for early validation
 	nameJa := r.FormValue("nameJa")
-	nameEn := r.FormValue("nameEn")
+	nameEn := strings.TrimSpace(r.FormValue("nameEn"))
+	reading := strings.TrimSpace(r.FormValue("reading"))
 	priceStr := r.FormValue("price")
+	category := strings.TrimSpace(r.FormValue("category"))
+	allergens := parseAllergens(r.FormValue("allergens"))
+	details, detailErrors := parseDishDetails(formField(r.Form))
 
 	// Convert price to integer
 	price := 0
@@ -44,96 +63,108 @@ func PostDish(w http.ResponseWriter, r *http.Request) {
 
 	// バリデーション用のリクエスト構造体を作成
 	dishRequest := CreateDishRequest{
-		NameJa: nameJa,
-		NameEn: nameEn,
-		Price:  price,
+		NameJa:    nameJa,
+		NameEn:    nameEn,
+		Reading:   reading,
+		Price:     price,
+		Category:  category,
+		Allergens: allergens,
+
+		DishDetailsRequest: details,
 	}
 
 	// バリデーション実行（ファイルアップロード前に実行）
-	if validationErrors := validateCreateDishRequest(dishRequest); len(validationErrors) > 0 {
+	if validationErrors := append(detailErrors, validateCreateDishRequest(dishRequest)...); len(validationErrors) > 0 {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
 		return
 	}
 
-	// ファイル取得とバリデーション
-	file, header, err := r.FormFile("photo")
+	conn, err := db.ConnectDB()
 	if err != nil {
-		writeErrorResponse(w, http.StatusBadRequest, "写真", "写真ファイルが選択されていません")
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
 		return
 	}
-	defer file.Close()
+	defer conn.Release()
 
-	// ファイル形式のバリデーション
-	if !isValidImageFormat(header.Filename) {
-		writeErrorResponse(w, http.StatusBadRequest, "写真", "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています")
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
 		return
 	}
 
-	// Generate secure file name with timestamp and UUID
-	uniqueFileName := generateSecureFileName(header.Filename)
-
-	// Upload file to GCS
-	ctx := context.Background()
-	gcsClient, err := utils.GetGCSClient()
-	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "ストレージ", "ストレージクライアントの取得に失敗しました")
+	// 写真の取得（photoKey で直接アップロード済みの写真を指定するか、photo でファイルを送信する）
+	// データベースにはファイル名のみを保存（署名付きURLは取得時に生成）
+	photo, ok := receivePhoto(r.Context(), w, r, conn, gcsClient, true)
+	if !ok {
 		return
 	}
 
-	// ファイルの内容を読み取り
-	fileBytes, err := io.ReadAll(file)
+	// Create dish struct
+	d := model.Dish{
+		NameJa:    nameJa,
+		NameEn:    nameEn,
+		Reading:   reading,
+		Price:     price,
+		Img:       photo.objectName,
+		Category:  category,
+		Allergens: allergens,
+
+		DishDetails: dishDetails(details),
+	}
+
+	tx, err := conn.Begin(r.Context())
 	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "写真", "ファイルの読み取りに失敗しました")
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
 		return
 	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
 
-	// Content-Typeを設定
-	ext := getFileExtension(header.Filename)
-	contentType := "image/jpeg"
-	switch ext {
-	case ".png":
-		contentType = "image/png"
-	case ".webp":
-		contentType = "image/webp"
-	}
+	row := tx.QueryRow(r.Context(),
+		`INSERT INTO dishes (name_ja, name_en, reading, search_text, price, photo_url, category, allergens, `+dishDetailColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, `+dishDetailPlaceholders(9)+`) RETURNING id`,
+		append([]any{d.NameJa, d.NameEn, d.Reading, dishSearchText(d.NameJa, d.NameEn, d.Reading), d.Price, d.Img, d.Category, d.Allergens}, dishDetailArgs(d.DishDetails)...)...,
+	)
 
-	// GCSにファイルをアップロード
-	if err := gcsClient.UploadFile(ctx, uniqueFileName, fileBytes, contentType); err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "写真", "写真のアップロードに失敗しました")
+	var id string
+	if err := row.Scan(&id); err != nil {
+		writeServerError(w, r, err, "データベース", "料理の登録に失敗しました")
 		return
 	}
 
-	// Set the photo URL in the Dish struct
-	// データベースにはファイル名のみを保存（署名付きURLは取得時に生成）
-	photoURL := uniqueFileName
+	// 直接アップロードした写真のアップロード枠を使用済みにする
+	if photo.fromSlot() {
+		if err := consumeUpload(r.Context(), tx, photo.photoKey); err != nil {
+			writeUploadError(w, r, err)
+			return
+		}
+	}
 
-	// Create dish struct
-	d := model.Dish{
-		NameJa: nameJa,
-		NameEn: nameEn,
-		Price:  price,
-		Img:    photoURL,
+	// 写真を代表写真として登録
+	if err := syncCoverPhoto(r.Context(), tx, id, d.Img); err != nil {
+		writeServerError(w, r, err, "データベース", "写真の登録に失敗しました")
+		return
 	}
 
-	conn, err := db.ConnectDB()
-	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "データベース", "データベース接続に失敗しました")
+	// 変更履歴を記録
+	if err := recordDishRevision(r.Context(), tx, id, model.RevisionCreate, nil, dishSnapshot(d), actorFromRequest(r)); err != nil {
+		writeServerError(w, r, err, "データベース", "変更履歴の記録に失敗しました")
 		return
 	}
-	defer conn.Close(context.Background())
 
-	row := conn.QueryRow(context.Background(),
-		`INSERT INTO dishes (name_ja, name_en, price, photo_url) VALUES ($1, $2, $3, $4) RETURNING id`,
-		d.NameJa, d.NameEn, d.Price, d.Img,
-	)
+	// 他の言語の料理名の下書きを作成する（失敗しても料理の登録は取り消さない）
+	d.ID = id
+	if err := suggestTranslations(r.Context(), tx, d); err != nil {
+		slog.ErrorContext(r.Context(), "翻訳の下書きの作成に失敗しました", "dish_id", id, "error", err)
+	}
 
-	var id string
-	if err := row.Scan(&id); err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "データベース", "料理の登録に失敗しました")
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "料理の登録に失敗しました")
 		return
 	}
+	metrics.DishesCreated.WithLabelValues("api").Inc()
+	releaseUpload(r.Context(), gcsClient, photo)
+	metrics.PhotosUploaded.WithLabelValues(uploadMethod(photo.fromSlot())).Inc()
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
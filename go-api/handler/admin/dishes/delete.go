@@ -2,45 +2,83 @@ package admin
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
 	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/model"
 )
 
 // 料理削除ハンドラー
 // @Summary 料理削除
-// @Description ID指定で料理を削除します
+// @Description ID指定で料理をゴミ箱に移動します（保持期間を過ぎると、他から参照されていない写真のファイルとともに完全に削除されます）
 // @Tags dishes
 // @Param id path string true "料理ID"
 // @Success 204 {string} string "No Content"
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /dishes/{id} [delete]
 func DeleteDish(w http.ResponseWriter, r *http.Request) {
 	// URLパラメータからIDを取得
 	vars := mux.Vars(r)
 	id := vars["id"]
 	if id == "" {
-		http.Error(w, "IDが指定されていません", http.StatusBadRequest)
+		writeErrorResponse(w, http.StatusBadRequest, "ID", "IDが指定されていません")
 		return
 	}
 
 	conn, err := db.ConnectDB()
 	if err != nil {
-		http.Error(w, "DB接続失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
 		return
 	}
-	defer conn.Close(context.Background())
+	defer conn.Release()
 
-	result, err := conn.Exec(context.Background(), "DELETE FROM dishes WHERE id = $1", id)
+	tx, err := conn.Begin(r.Context())
 	if err != nil {
-		http.Error(w, "削除失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "データベース", "削除に失敗しました")
 		return
 	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
 
-	if result.RowsAffected() == 0 {
-		http.Error(w, "指定されたIDの料理が見つかりません", http.StatusNotFound)
+	// セットメニューの既定の料理は削除しない（ゴミ箱から完全削除できず、セットの価格も算出できなくなるため）
+	var isBundleDefault bool
+	err = tx.QueryRow(r.Context(), `SELECT EXISTS (SELECT 1 FROM bundle_slots WHERE default_dish_id = $1)`, id).Scan(&isBundleDefault)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "削除に失敗しました")
+		return
+	}
+	if isBundleDefault {
+		writeErrorResponse(w, http.StatusConflict, "料理", "セットメニューの既定の料理は削除できません。先にセットメニューの既定の料理を変更してください")
+		return
+	}
+
+	var deleted model.Dish
+	err = scanDish(tx.QueryRow(r.Context(),
+		"UPDATE dishes SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL RETURNING "+dishColumns,
+		id,
+	), &deleted)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "削除に失敗しました")
+		return
+	}
+
+	// 変更履歴を記録
+	if err := recordDishRevision(r.Context(), tx, id, model.RevisionDelete, dishSnapshot(deleted), nil, actorFromRequest(r)); err != nil {
+		writeServerError(w, r, err, "データベース", "変更履歴の記録に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "削除に失敗しました")
 		return
 	}
 
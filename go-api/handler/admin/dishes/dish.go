@@ -0,0 +1,289 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/config"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/pricing"
+	"github.com/smilemasa/go-api/search"
+)
+
+// dishDetailColumns 料理の詳細情報のカラム（dishDetailFormFields・dishDetailArgs と順番を合わせる）
+const dishDetailColumns = "description_ja, description_en, ingredients, calories, protein, fat, carbohydrate, spice_level, portion_size"
+
+// dishColumns dishes テーブルから取得するカラム（dishFields と順番を合わせる）
+const dishColumns = "id, name_ja, name_en, reading, price, photo_url, category, allergens, " + dishDetailColumns
+
+// dishDetailFormFields 詳細情報のフォーム項目名（dishDetailColumns の順）
+var dishDetailFormFields = []string{"descriptionJa", "descriptionEn", "ingredients", "calories", "protein", "fat", "carbohydrate", "spiceLevel", "portionSize"}
+
+// dishFields dishColumns の順に読み込み先を並べる
+func dishFields(d *model.Dish) []any {
+	return []any{
+		&d.ID, &d.NameJa, &d.NameEn, &d.Reading, &d.Price, &d.Img, &d.Category, &d.Allergens,
+		&d.DescriptionJa, &d.DescriptionEn, &d.Ingredients,
+		&d.Nutrition.Calories, &d.Nutrition.Protein, &d.Nutrition.Fat, &d.Nutrition.Carbohydrate,
+		&d.SpiceLevel, &d.PortionSize,
+	}
+}
+
+// scanDish dishColumns で取得した行を model.Dish に読み込む
+func scanDish(row pgx.Row, d *model.Dish) error {
+	if err := row.Scan(dishFields(d)...); err != nil {
+		return err
+	}
+	d.BasePrice = d.Price
+	return nil
+}
+
+// dishDetailArgs 詳細情報を dishDetailColumns の順に並べる（原材料が未設定の場合は空の配列）
+func dishDetailArgs(d model.DishDetails) []any {
+	ingredients := d.Ingredients
+	if ingredients == nil {
+		ingredients = []string{}
+	}
+	return []any{
+		d.DescriptionJa, d.DescriptionEn, ingredients,
+		d.Nutrition.Calories, d.Nutrition.Protein, d.Nutrition.Fat, d.Nutrition.Carbohydrate,
+		d.SpiceLevel, d.PortionSize,
+	}
+}
+
+// dishDetailPlaceholders INSERT 文の詳細情報のプレースホルダー（$start から順に）
+func dishDetailPlaceholders(start int) string {
+	placeholders := make([]string, len(dishDetailFormFields))
+	for i := range placeholders {
+		placeholders[i] = "$" + strconv.Itoa(start+i)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// dishDetailAssignments UPDATE 文で詳細情報を設定する式（$start から順に）
+func dishDetailAssignments(start int) string {
+	columns := strings.Split(dishDetailColumns, ", ")
+	for i, c := range columns {
+		columns[i] = c + " = $" + strconv.Itoa(start+i)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// dishDetailValues 詳細情報を dishDetailColumns の順に文字列で並べる（CSVの列など）
+// 原材料はカンマ区切り、未登録の栄養成分は空文字
+func dishDetailValues(d model.DishDetails) []string {
+	values := []string{d.DescriptionJa, d.DescriptionEn, strings.Join(d.Ingredients, ","), "", "", "", "", strconv.Itoa(d.SpiceLevel), d.PortionSize}
+	if d.Nutrition.Calories != nil {
+		values[3] = strconv.Itoa(*d.Nutrition.Calories)
+	}
+	for i, v := range []*float64{d.Nutrition.Protein, d.Nutrition.Fat, d.Nutrition.Carbohydrate} {
+		if v != nil {
+			values[4+i] = strconv.FormatFloat(*v, 'f', -1, 64)
+		}
+	}
+	return values
+}
+
+// formField フォームの項目の値と、項目が送信されたかどうかを返す関数を作成する
+func formField(form url.Values) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		values, ok := form[name]
+		if !ok || len(values) == 0 {
+			return "", ok
+		}
+		return values[0], true
+	}
+}
+
+// parseDishDetails 詳細情報の項目を読み取る（value はフォーム項目名で値を返す関数）
+// 数値として読み取れない項目はエラーとして返す
+func parseDishDetails(value func(name string) (string, bool)) (DishDetailsRequest, []ValidationError) {
+	var req DishDetailsRequest
+	var errs []ValidationError
+	get := func(name string) string {
+		v, _ := value(name)
+		return strings.TrimSpace(v)
+	}
+
+	req.DescriptionJa = get("descriptionJa")
+	req.DescriptionEn = get("descriptionEn")
+	req.Ingredients = parseIngredients(get("ingredients"))
+	req.PortionSize = get("portionSize")
+
+	if v := strings.ReplaceAll(get("calories"), ",", ""); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.Calories = &n
+		} else {
+			errs = append(errs, ValidationError{Field: getFieldName("Calories"), Message: "整数で入力してください"})
+		}
+	}
+	for _, f := range []struct {
+		name  string
+		field string
+		dest  **float64
+	}{
+		{"protein", "Protein", &req.Protein},
+		{"fat", "Fat", &req.Fat},
+		{"carbohydrate", "Carbohydrate", &req.Carbohydrate},
+	} {
+		if v := get(f.name); v != "" {
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				*f.dest = &n
+			} else {
+				errs = append(errs, ValidationError{Field: getFieldName(f.field), Message: "数値で入力してください"})
+			}
+		}
+	}
+	if v := get("spiceLevel"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.SpiceLevel = n
+		} else {
+			errs = append(errs, ValidationError{Field: getFieldName("SpiceLevel"), Message: "整数で入力してください"})
+		}
+	}
+
+	return req, errs
+}
+
+// dishDetails リクエストの詳細情報を料理の詳細情報に変換する
+func dishDetails(req DishDetailsRequest) model.DishDetails {
+	return model.DishDetails{
+		DescriptionJa: req.DescriptionJa,
+		DescriptionEn: req.DescriptionEn,
+		Ingredients:   req.Ingredients,
+		Nutrition: model.Nutrition{
+			Calories:     req.Calories,
+			Protein:      req.Protein,
+			Fat:          req.Fat,
+			Carbohydrate: req.Carbohydrate,
+		},
+		SpiceLevel:  req.SpiceLevel,
+		PortionSize: req.PortionSize,
+	}
+}
+
+// applyDishDetails 送信された詳細情報の項目だけを料理に反映する（空文字は解除）
+func applyDishDetails(d *model.DishDetails, req DishDetailsRequest, value func(name string) (string, bool)) {
+	sent := func(name string) bool {
+		_, ok := value(name)
+		return ok
+	}
+
+	if sent("descriptionJa") {
+		d.DescriptionJa = req.DescriptionJa
+	}
+	if sent("descriptionEn") {
+		d.DescriptionEn = req.DescriptionEn
+	}
+	if sent("ingredients") {
+		d.Ingredients = req.Ingredients
+	}
+	if sent("calories") {
+		d.Nutrition.Calories = req.Calories
+	}
+	if sent("protein") {
+		d.Nutrition.Protein = req.Protein
+	}
+	if sent("fat") {
+		d.Nutrition.Fat = req.Fat
+	}
+	if sent("carbohydrate") {
+		d.Nutrition.Carbohydrate = req.Carbohydrate
+	}
+	if sent("spiceLevel") {
+		d.SpiceLevel = req.SpiceLevel
+	}
+	if sent("portionSize") {
+		d.PortionSize = req.PortionSize
+	}
+}
+
+// parseIngredients 原材料（カンマ・読点・改行区切り）を重複を除いて配列にする（未指定の場合は空の配列）
+func parseIngredients(value string) []string {
+	ingredients := []string{}
+	seen := map[string]bool{}
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '、' || r == '，' || r == '\n' || r == '\r'
+	}) {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		ingredients = append(ingredients, item)
+	}
+	return ingredients
+}
+
+// dishSearchText 料理の検索対象文字列（search_text カラムの値）を作成する
+func dishSearchText(nameJa, nameEn, reading string) string {
+	return search.Document(nameJa, nameEn, reading)
+}
+
+// parseAllergens カンマ区切りのアレルゲンを重複を除いて配列にする（未指定の場合は空の配列）
+func parseAllergens(value string) []string {
+	allergens := []string{}
+	seen := map[string]bool{}
+	for _, a := range strings.Split(value, ",") {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		allergens = append(allergens, a)
+	}
+	return allergens
+}
+
+// parsePriceAt クエリパラメータ at（RFC3339）から価格を判定する日時を取得する
+// 指定がない場合は現在日時
+func parsePriceAt(r *http.Request) (time.Time, error) {
+	at := r.URL.Query().Get("at")
+	if at == "" {
+		return time.Now(), nil
+	}
+	return time.Parse(time.RFC3339, at)
+}
+
+// loadPriceResolver 指定日時の価格解決に必要な価格変更と有効な価格ルールを取得する
+func loadPriceResolver(ctx context.Context, q db.Querier, at time.Time) (*pricing.Resolver, error) {
+	// 料理ごとに指定日時以前で最新の価格変更のみを取得
+	rows, err := q.Query(ctx,
+		`SELECT DISTINCT ON (dish_id) id, dish_id, price, effective_at
+		 FROM price_changes WHERE effective_at <= $1
+		 ORDER BY dish_id, effective_at DESC`,
+		at,
+	)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PriceChange, error) {
+		var c model.PriceChange
+		err := row.Scan(&c.ID, &c.DishID, &c.Price, &c.EffectiveAt)
+		return c, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := loadPriceRules(ctx, q, "WHERE active")
+	if err != nil {
+		return nil, err
+	}
+
+	return pricing.NewResolver(changes, rules, config.Get().Pricing.TimeZone), nil
+}
+
+// applyEffectivePrice 料理の価格を指定日時の実売価格に置き換える
+func applyEffectivePrice(resolver *pricing.Resolver, d *model.Dish, at time.Time) {
+	res := resolver.Resolve(d.ID, d.Category, d.Price, at)
+	d.Price = res.Price
+	d.BasePrice = res.BasePrice
+	d.PriceRuleID = res.RuleID
+}
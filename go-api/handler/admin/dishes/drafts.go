@@ -0,0 +1,487 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/config"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/i18n"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/translation"
+)
+
+// 翻訳下書き一覧取得ハンドラー
+// @Summary 翻訳下書き一覧取得
+// @Description 料理の登録・更新時に、料理名が登録されていないロケールについて機械翻訳で作成された、承認待ちの料理名の下書きを取得します
+// @Tags translations
+// @Produce json
+// @Param locale query string false "対象のロケール（en, zh-Hans, zh-Hant, ko、省略時はすべて）"
+// @Success 200 {array} model.TranslationDraft
+// @Failure 400 {object} ErrorResponse
+// @Router /translations/drafts [get]
+func GetTranslationDrafts(w http.ResponseWriter, r *http.Request) {
+	locale := ""
+	if v := r.URL.Query().Get("locale"); v != "" {
+		var ok bool
+		if locale, ok = draftLocale(w, v); !ok {
+			return
+		}
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(r.Context(),
+		`SELECT dr.dish_id, d.name_ja, dr.locale, dr.name,
+		        COALESCE(CASE WHEN dr.locale = $1 THEN d.name_en ELSE t.name END, ''),
+		        dr.source, dr.created_at
+		 FROM dish_translation_drafts dr
+		 JOIN dishes d ON d.id = dr.dish_id AND d.deleted_at IS NULL
+		 LEFT JOIN dish_translations t ON t.dish_id = dr.dish_id AND t.locale = dr.locale
+		 WHERE $2 = '' OR dr.locale = $2
+		 ORDER BY d.name_ja, dr.locale`,
+		i18n.English, locale,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の下書きの取得に失敗しました")
+		return
+	}
+	drafts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.TranslationDraft, error) {
+		var d model.TranslationDraft
+		err := row.Scan(&d.DishID, &d.NameJa, &d.Locale, &d.Name, &d.Current, &d.Source, &d.CreatedAt)
+		return d, err
+	})
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の下書きの取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(drafts)
+}
+
+// 翻訳下書き承認ハンドラー
+// @Summary 翻訳下書き承認
+// @Description 料理名の下書きを承認して反映します。英語は料理の英語名を更新し（変更履歴を記録）、それ以外のロケールは翻訳として登録します。リクエストボディで料理名を修正して承認することもできます
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param locale path string true "ロケール（en, zh-Hans, zh-Hant, ko）"
+// @Param body body TranslationRequest false "修正した料理名・説明（省略時は下書きのまま承認）"
+// @Success 200 {object} model.DishTranslation
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/translation-drafts/{locale}/approve [post]
+func ApproveTranslationDraft(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dishID := vars["id"]
+
+	locale, ok := draftLocale(w, vars["locale"])
+	if !ok {
+		return
+	}
+
+	// ボディがない場合は下書きのまま承認する（chunked で送られた空のボディも含む）
+	var req TranslationRequest
+	edited := true
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if !errors.Is(err, io.EOF) {
+			writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+			return
+		}
+		edited = false
+	}
+	if edited {
+		if validationErrors := validateTranslationRequest(req); len(validationErrors) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+			return
+		}
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	var draftName string
+	err = tx.QueryRow(r.Context(),
+		`DELETE FROM dish_translation_drafts dr USING dishes d
+		 WHERE dr.dish_id = $1 AND dr.locale = $2 AND d.id = dr.dish_id AND d.deleted_at IS NULL
+		 RETURNING dr.name`,
+		dishID, locale,
+	).Scan(&draftName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "翻訳の下書き", "指定されたロケールの下書きが見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "翻訳の下書きの取得に失敗しました")
+		return
+	}
+	if !edited {
+		req.Name = draftName
+	}
+
+	t := model.DishTranslation{DishID: dishID, Locale: locale, Name: req.Name, Description: req.Description}
+	if locale == i18n.English {
+		// 英語名は dishes に保存するため、料理の更新として変更履歴を記録する
+		var current model.Dish
+		row := tx.QueryRow(r.Context(),
+			`SELECT `+dishColumns+` FROM dishes WHERE id = $1 FOR UPDATE`,
+			dishID,
+		)
+		if err := scanDish(row, &current); err != nil {
+			writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+			return
+		}
+		updated := current
+		updated.NameEn = req.Name
+
+		err = tx.QueryRow(r.Context(),
+			`UPDATE dishes SET name_en = $1, search_text = $2 WHERE id = $3 RETURNING now()`,
+			updated.NameEn, dishSearchText(updated.NameJa, updated.NameEn, updated.Reading), dishID,
+		).Scan(&t.UpdatedAt)
+		if err != nil {
+			writeServerError(w, r, err, "データベース", "料理名の更新に失敗しました")
+			return
+		}
+		if err := recordDishRevision(r.Context(), tx, dishID, model.RevisionUpdate, dishSnapshot(current), dishSnapshot(updated), actorFromRequest(r)); err != nil {
+			writeServerError(w, r, err, "データベース", "変更履歴の記録に失敗しました")
+			return
+		}
+		t.Description = ""
+	} else {
+		// 説明を指定しない場合は登録済みの説明を残す
+		err = tx.QueryRow(r.Context(),
+			`INSERT INTO dish_translations (dish_id, locale, name, description) VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (dish_id, locale) DO UPDATE SET name = EXCLUDED.name,
+			   description = COALESCE(NULLIF(EXCLUDED.description, ''), dish_translations.description), updated_at = now()
+			 RETURNING description, updated_at`,
+			dishID, locale, req.Name, req.Description,
+		).Scan(&t.Description, &t.UpdatedAt)
+		if err != nil {
+			writeServerError(w, r, err, "データベース", "翻訳の登録に失敗しました")
+			return
+		}
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の登録に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t)
+}
+
+// 翻訳下書き却下ハンドラー
+// @Summary 翻訳下書き却下
+// @Description 料理名の下書きを反映せずに削除します
+// @Tags translations
+// @Param id path string true "料理ID"
+// @Param locale path string true "ロケール（en, zh-Hans, zh-Hant, ko）"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/translation-drafts/{locale} [delete]
+func DeleteTranslationDraft(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	locale, ok := draftLocale(w, vars["locale"])
+	if !ok {
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	result, err := conn.Exec(r.Context(),
+		`DELETE FROM dish_translation_drafts WHERE dish_id = $1 AND locale = $2`,
+		vars["id"], locale,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の下書きの削除に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "翻訳の下書き", "指定されたロケールの下書きが見つかりません")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 用語集取得ハンドラー
+// @Summary 用語集取得
+// @Description 辞書翻訳で使う用語集（日本語の用語と訳語）を取得します
+// @Tags translations
+// @Produce json
+// @Param locale query string false "対象のロケール（省略時はすべて）"
+// @Success 200 {array} model.GlossaryEntry
+// @Failure 400 {object} ErrorResponse
+// @Router /translations/glossary [get]
+func GetGlossary(w http.ResponseWriter, r *http.Request) {
+	locale := ""
+	if v := r.URL.Query().Get("locale"); v != "" {
+		var ok bool
+		if locale, ok = draftLocale(w, v); !ok {
+			return
+		}
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(r.Context(),
+		`SELECT id, term, locale, translation, updated_at FROM translation_glossary
+		 WHERE $1 = '' OR locale = $1
+		 ORDER BY term, locale`,
+		locale,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "用語集の取得に失敗しました")
+		return
+	}
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.GlossaryEntry, error) {
+		var e model.GlossaryEntry
+		err := row.Scan(&e.ID, &e.Term, &e.Locale, &e.Translation, &e.UpdatedAt)
+		return e, err
+	})
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "用語集の取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// 用語登録・更新ハンドラー
+// @Summary 用語登録・更新
+// @Description 用語集に用語と訳語を登録します（同じ用語・ロケールが登録済みの場合は訳語を上書き）
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param body body GlossaryEntryRequest true "用語"
+// @Success 200 {object} model.GlossaryEntry
+// @Failure 400 {object} ErrorResponse
+// @Router /translations/glossary [post]
+func PostGlossaryEntry(w http.ResponseWriter, r *http.Request) {
+	var req GlossaryEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return
+	}
+
+	if validationErrors := validateGlossaryEntryRequest(req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return
+	}
+	locale, _ := i18n.Canonical(req.Locale)
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	var e model.GlossaryEntry
+	err = conn.QueryRow(r.Context(),
+		`INSERT INTO translation_glossary (term, locale, translation) VALUES ($1, $2, $3)
+		 ON CONFLICT (term, locale) DO UPDATE SET translation = EXCLUDED.translation, updated_at = now()
+		 RETURNING id, term, locale, translation, updated_at`,
+		req.Term, locale, req.Translation,
+	).Scan(&e.ID, &e.Term, &e.Locale, &e.Translation, &e.UpdatedAt)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "用語の登録に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(e)
+}
+
+// 用語削除ハンドラー
+// @Summary 用語削除
+// @Description ID指定で用語集から用語を削除します
+// @Tags translations
+// @Param id path string true "用語ID"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /translations/glossary/{id} [delete]
+func DeleteGlossaryEntry(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	result, err := conn.Exec(r.Context(), `DELETE FROM translation_glossary WHERE id = $1`, id)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "用語の削除に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "用語", "指定されたIDの用語が見つかりません")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// draftLocale 下書き・用語集の対象にできるロケール（日本語以外）かを確認し、表記を揃えて返す
+// 不正な場合はエラーレスポンスを書き込んで false を返す
+func draftLocale(w http.ResponseWriter, value string) (string, bool) {
+	locale, ok := i18n.Canonical(value)
+	if !ok || locale == i18n.Japanese {
+		writeErrorResponse(w, http.StatusBadRequest, "ロケール", "対応していないロケールです")
+		return "", false
+	}
+	return locale, true
+}
+
+// glossaryStore DBの用語集（translation.Glossary の実装）
+type glossaryStore struct {
+	q db.Querier
+}
+
+// Terms target ロケールの訳語を日本語の用語をキーにして返す
+func (g glossaryStore) Terms(ctx context.Context, target string) (map[string]string, error) {
+	rows, err := g.q.Query(ctx, `SELECT term, translation FROM translation_glossary WHERE locale = $1`, target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terms := map[string]string{}
+	for rows.Next() {
+		var term, translated string
+		if err := rows.Scan(&term, &translated); err != nil {
+			return nil, err
+		}
+		terms[term] = translated
+	}
+	return terms, rows.Err()
+}
+
+// suggestTranslations 料理名が登録されていないロケールについて、日本語名を機械翻訳した下書きを作成する
+// 料理名が登録済みのロケールの下書きは削除し、未登録のロケールの下書きは現在の日本語名の翻訳に置き換える
+// 料理の登録・更新と同じトランザクション内のセーブポイントで実行し、失敗した場合は下書きの変更のみ取り消す
+func suggestTranslations(ctx context.Context, tx pgx.Tx, d model.Dish) (err error) {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			sp.Rollback(context.WithoutCancel(ctx))
+		}
+	}()
+
+	translator, err := translation.New(config.Get().Translation.Backend, glossaryStore{q: sp})
+	if err != nil {
+		return err
+	}
+
+	current := map[string]string{i18n.English: d.NameEn}
+	translations, err := loadDishTranslations(ctx, sp, []string{d.ID})
+	if err != nil {
+		return err
+	}
+	for _, t := range translations {
+		current[t.Locale] = t.Name
+	}
+
+	names, err := draftNames(ctx, translator, d.NameJa, current)
+	if err != nil {
+		return err
+	}
+
+	for _, locale := range i18n.Supported {
+		if locale == i18n.Japanese {
+			continue
+		}
+
+		// 料理名が登録済み、または翻訳できないロケールは下書きを残さない
+		name := names[locale]
+		if name == "" {
+			if _, err = sp.Exec(ctx,
+				`DELETE FROM dish_translation_drafts WHERE dish_id = $1 AND locale = $2`, d.ID, locale,
+			); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err = sp.Exec(ctx,
+			`INSERT INTO dish_translation_drafts (dish_id, locale, name, source) VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (dish_id, locale) DO UPDATE SET name = EXCLUDED.name, source = EXCLUDED.source, created_at = now()
+			 WHERE (dish_translation_drafts.name, dish_translation_drafts.source) IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.source)`,
+			d.ID, locale, name, translator.Name(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return sp.Commit(ctx)
+}
+
+// draftNames 料理名が登録されていない（current が空の）ロケールについて、日本語名を機械翻訳した料理名をロケールをキーにして返す
+// 翻訳できないロケールは含めない
+func draftNames(ctx context.Context, translator translation.Translator, nameJa string, current map[string]string) (map[string]string, error) {
+	names := map[string]string{}
+	for _, locale := range i18n.Supported {
+		if locale == i18n.Japanese || current[locale] != "" {
+			continue
+		}
+
+		name, err := translator.Translate(ctx, nameJa, i18n.Japanese, locale)
+		if errors.Is(err, translation.ErrNoTranslation) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("translate to %s: %w", locale, err)
+		}
+		if name != "" {
+			names[locale] = name
+		}
+	}
+	return names, nil
+}
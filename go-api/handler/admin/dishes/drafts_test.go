@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/smilemasa/go-api/i18n"
+	"github.com/smilemasa/go-api/translation"
+)
+
+// mapGlossary ロケールごとの用語集（テスト用）
+type mapGlossary map[string]map[string]string
+
+func (g mapGlossary) Terms(_ context.Context, target string) (map[string]string, error) {
+	return g[target], nil
+}
+
+// 英語名を省略して登録した料理は、日本語名を翻訳した英語名の下書きを作成する
+func TestCreateDishWithoutNameEnDraftsEnglishName(t *testing.T) {
+	req := CreateDishRequest{NameJa: "カレーライス", Price: 800}
+	if errs := validateCreateDishRequest(req); len(errs) > 0 {
+		t.Fatalf("validateCreateDishRequest() = %v, want no errors", errs)
+	}
+
+	translator := translation.NewDictionary(mapGlossary{
+		i18n.English: {"カレー": "curry", "ライス": "rice"},
+		i18n.Korean:  {"カレー": "카레", "ライス": "라이스"},
+	})
+	tests := []struct {
+		name    string
+		current map[string]string
+		want    map[string]string
+	}{
+		{
+			name:    "name_en omitted",
+			current: map[string]string{i18n.English: req.NameEn},
+			want:    map[string]string{i18n.English: "curry rice", i18n.Korean: "카레 라이스"},
+		},
+		{
+			name:    "registered names are kept",
+			current: map[string]string{i18n.English: "Curry Rice", i18n.Korean: "카레라이스"},
+			want:    map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := draftNames(context.Background(), translator, req.NameJa, tt.current)
+			if err != nil {
+				t.Fatalf("draftNames() = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("draftNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/smilemasa/go-api/config"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// DishExportRecord エクスポートする料理（JSON形式はそのまま一括取り込みに使える）
+type DishExportRecord struct {
+	NameJa            string   `json:"nameJa"`    // 日本語名
+	NameEn            string   `json:"nameEn"`    // 英語名
+	Price             int      `json:"price"`     // 通常価格（価格ルール適用前）
+	Image             string   `json:"image"`     // 写真のオブジェクト名
+	Category          string   `json:"category"`  // カテゴリ
+	Reading           string   `json:"reading"`   // ふりがな
+	Allergens         []string `json:"allergens"` // アレルゲン
+	model.DishDetails          // 詳細情報（説明・原材料・栄養成分など）
+}
+
+// メニューエクスポートハンドラー
+// @Summary メニューエクスポート
+// @Description 現在のメニューをCSV・JSON・PDFで出力します。CSVとJSONは一括取り込みと同じ形式です
+// @Tags dishes
+// @Produce text/csv,application/json,application/pdf
+// @Param format query string false "出力形式（csv, json, pdf。省略時はcsv）"
+// @Param category query string false "カテゴリで絞り込み"
+// @Param at query string false "PDFに載せる価格を判定する日時（RFC3339、省略時は現在日時）"
+// @Success 200 {array} DishExportRecord
+// @Failure 400 {object} ErrorResponse
+// @Router /dishes/export [get]
+func ExportDishes(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" && format != "pdf" {
+		writeErrorResponse(w, http.StatusBadRequest, "出力形式", "出力形式はcsv、json、pdfのいずれかを指定してください")
+		return
+	}
+
+	at, err := parsePriceAt(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "日時", "at はRFC3339形式で指定してください")
+		return
+	}
+
+	fontPath := config.Get().Export.FontPath
+	if format == "pdf" && fontPath == "" {
+		writeServerError(w, r, errors.New("MENU_PDF_FONT_PATH is not set"), "PDF", "PDF用の日本語フォントが設定されていません")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	category := r.URL.Query().Get("category")
+	rows, err := conn.Query(r.Context(),
+		`SELECT `+dishColumns+` FROM dishes
+		 WHERE deleted_at IS NULL AND ($1 = '' OR category = $1)
+		 ORDER BY category, name_ja`,
+		category,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	dishes := []model.Dish{}
+	for rows.Next() {
+		var d model.Dish
+		if err := scanDish(rows, &d); err != nil {
+			rows.Close()
+			writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+			return
+		}
+		dishes = append(dishes, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+
+	resolver, err := loadPriceResolver(r.Context(), conn, at)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+	for i := range dishes {
+		applyEffectivePrice(resolver, &dishes[i], at)
+	}
+
+	filename := "menu_" + at.In(config.Get().Pricing.TimeZone).Format("20060102")
+
+	switch format {
+	case "json":
+		records := make([]DishExportRecord, 0, len(dishes))
+		for _, d := range dishes {
+			records = append(records, exportRecord(d))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+		json.NewEncoder(w).Encode(records)
+
+	case "csv":
+		var buf bytes.Buffer
+		// Excelで文字化けしないようにBOMを付ける
+		buf.WriteString("\ufeff")
+		cw := csv.NewWriter(&buf)
+		cw.Write(importColumns)
+		for _, d := range dishes {
+			rec := exportRecord(d)
+			cw.Write(append([]string{rec.NameJa, rec.NameEn, strconv.Itoa(rec.Price), rec.Image, rec.Category, rec.Reading, strings.Join(rec.Allergens, ",")}, dishDetailValues(rec.DishDetails)...))
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			writeServerError(w, r, err, "CSV", "CSVの作成に失敗しました")
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		w.Write(buf.Bytes())
+
+	case "pdf":
+		gcsClient, err := utils.GetGCSClient()
+		if err != nil {
+			writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+			return
+		}
+		var buf bytes.Buffer
+		if err := writeMenuPDF(r.Context(), &buf, gcsClient, fontPath, dishes, at); err != nil {
+			writeServerError(w, r, err, "PDF", "PDFの作成に失敗しました")
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.pdf"`)
+		w.Write(buf.Bytes())
+	}
+}
+
+// exportRecord 料理をエクスポート形式に変換する
+func exportRecord(d model.Dish) DishExportRecord {
+	return DishExportRecord{
+		NameJa:    d.NameJa,
+		NameEn:    d.NameEn,
+		Price:     d.BasePrice,
+		Image:     utils.ObjectName(d.Img),
+		Category:  d.Category,
+		Reading:   d.Reading,
+		Allergens: d.Allergens,
+
+		DishDetails: d.DishDetails,
+	}
+}
+
+// writeMenuPDF 日英併記の印刷用メニューをPDFで出力する
+// 写真はGCSから取得し、PDFに埋め込めない形式（webp）や取得に失敗した写真は省略する
+func writeMenuPDF(ctx context.Context, buf *bytes.Buffer, gcsClient *utils.GCSClient, fontPath string, dishes []model.Dish, at time.Time) error {
+	const (
+		font       = "menu"
+		photoW     = 36.0
+		photoH     = 27.0
+		rowH       = 32.0
+		pageBottom = 280.0
+	)
+
+	fontData, err := os.ReadFile(fontPath)
+	if err != nil {
+		return err
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(font, "", fontData)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(false, 15)
+	pdf.AddPage()
+
+	pdf.SetFont(font, "", 20)
+	pdf.CellFormat(0, 12, "メニュー / Menu", "", 1, "C", false, 0, "")
+	pdf.SetFont(font, "", 9)
+	pdf.CellFormat(0, 6, at.In(config.Get().Pricing.TimeZone).Format("2006/01/02 15:04")+" 時点の価格", "", 1, "R", false, 0, "")
+
+	category := "\x00"
+	for _, d := range dishes {
+		if d.Category != category {
+			category = d.Category
+			if pdf.GetY()+10+rowH > pageBottom {
+				pdf.AddPage()
+			}
+			heading := category
+			if heading == "" {
+				heading = "その他 / Others"
+			}
+			pdf.Ln(4)
+			pdf.SetFont(font, "", 14)
+			pdf.CellFormat(0, 8, heading, "B", 1, "L", false, 0, "")
+			pdf.Ln(2)
+		}
+
+		if pdf.GetY()+rowH > pageBottom {
+			pdf.AddPage()
+		}
+
+		x, y := pdf.GetX(), pdf.GetY()
+		if name := menuPhoto(ctx, pdf, gcsClient, d.Img); name != "" {
+			pdf.ImageOptions(name, x, y, photoW, photoH, false, fpdf.ImageOptions{}, 0, "")
+		}
+
+		textX := x + photoW + 5
+		pdf.SetXY(textX, y+2)
+		pdf.SetFont(font, "", 13)
+		pdf.CellFormat(110, 7, d.NameJa, "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, fmt.Sprintf("¥%s", formatYen(d.Price)), "", 1, "R", false, 0, "")
+		pdf.SetX(textX)
+		pdf.SetFont(font, "", 10)
+		pdf.CellFormat(110, 6, d.NameEn, "", 0, "L", false, 0, "")
+		if d.Price != d.BasePrice {
+			pdf.SetFont(font, "", 8)
+			pdf.CellFormat(0, 6, fmt.Sprintf("通常 ¥%s", formatYen(d.BasePrice)), "", 1, "R", false, 0, "")
+		}
+
+		pdf.SetXY(x, y+rowH)
+	}
+
+	return pdf.Output(buf)
+}
+
+// menuPhoto 写真をPDFに登録し、登録名を返す（埋め込めない場合は空文字）
+func menuPhoto(ctx context.Context, pdf *fpdf.Fpdf, gcsClient *utils.GCSClient, img string) string {
+	if img == "" {
+		return ""
+	}
+
+	objectName := utils.ObjectName(img)
+	var imageType string
+	switch getFileExtension(objectName) {
+	case ".jpg", ".jpeg":
+		imageType = "JPG"
+	case ".png":
+		imageType = "PNG"
+	default:
+		return ""
+	}
+
+	data, err := gcsClient.ReadFile(ctx, objectName)
+	if err != nil {
+		return ""
+	}
+
+	info := pdf.RegisterImageOptionsReader(objectName, fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(data))
+	if !pdf.Ok() || info == nil {
+		// 壊れた画像で全体が失敗しないようにエラーを解除する
+		pdf.ClearError()
+		return ""
+	}
+	return objectName
+}
+
+// formatYen 金額を3桁区切りの文字列にする
+func formatYen(price int) string {
+	s := strconv.Itoa(price)
+	if price < 0 {
+		return s
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
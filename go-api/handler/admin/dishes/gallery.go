@@ -0,0 +1,620 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/metrics"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// maxDishPhotos 料理1件あたりの写真の上限
+const maxDishPhotos = 20
+
+// 料理写真一覧取得ハンドラー
+// @Summary 料理写真一覧取得
+// @Description 料理の写真を表示順に取得します（画像URLは署名付きURL）
+// @Tags photos
+// @Produce json
+// @Param id path string true "料理ID"
+// @Success 200 {array} model.DishPhoto
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/photos [get]
+func GetDishPhotos(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	writeDishPhotos(w, r, conn, dishID, http.StatusOK)
+}
+
+// 料理写真追加ハンドラー
+// @Summary 料理写真追加
+// @Description 料理のギャラリーに写真を追加します（複数可、末尾に追加）。cover を指定すると最初の写真を代表写真にします。スタッフの顔写真など公開しない写真は public=false を指定すると、常に署名付きURLで配信されます
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param photo formData file false "写真ファイル（複数指定可）"
+// @Param photoKey formData string false "直接アップロードした写真のキー（複数指定可、photo と併用可）"
+// @Param cover formData bool false "追加した写真を代表写真にする"
+// @Param public formData bool false "署名なしのURLで配信してよい写真かどうか（省略時は true。false の写真は代表写真にできない）"
+// @Success 201 {array} model.DishPhoto
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/photos [post]
+func PostDishPhotos(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "フォーム", "フォームデータの解析に失敗しました")
+		return
+	}
+	headers := r.MultipartForm.File["photo"]
+	var photoKeys []string
+	for _, key := range r.MultipartForm.Value["photoKey"] {
+		if key = strings.TrimSpace(key); key != "" {
+			photoKeys = append(photoKeys, key)
+		}
+	}
+	if len(headers)+len(photoKeys) == 0 {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", "写真ファイルまたは photoKey が指定されていません")
+		return
+	}
+	for _, header := range headers {
+		if !isValidImageFormat(header.Filename) {
+			writeErrorResponse(w, http.StatusBadRequest, "写真", "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています")
+			return
+		}
+		if header.Size > maxPhotoSize {
+			writeErrorResponse(w, http.StatusBadRequest, "写真", fmt.Sprintf("写真は%dMBまでアップロードできます", maxPhotoSize>>20))
+			return
+		}
+	}
+	cover, _ := strconv.ParseBool(r.FormValue("cover"))
+	public := true
+	if publicStr := r.FormValue("public"); publicStr != "" {
+		var err error
+		if public, err = strconv.ParseBool(publicStr); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "公開", "public は true または false で指定してください")
+			return
+		}
+	}
+	if cover && !public {
+		writeErrorResponse(w, http.StatusBadRequest, "代表写真", "非公開の写真は代表写真にできません")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	// 上限を超える場合はアップロード前に拒否する（同時に登録された場合に備えて、登録時にも確認する）
+	var count int
+	if err := conn.QueryRow(r.Context(), `SELECT count(*) FROM dish_photos WHERE dish_id = $1`, dishID).Scan(&count); err != nil {
+		writeServerError(w, r, err, "データベース", "写真の取得に失敗しました")
+		return
+	}
+	if count+len(headers)+len(photoKeys) > maxDishPhotos {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", fmt.Sprintf("写真は1つの料理につき%d枚まで登録できます", maxDishPhotos))
+		return
+	}
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	// 直接アップロードされた写真を確認し、保存先のオブジェクト名にする
+	received := make([]receivedPhoto, 0, len(photoKeys))
+	for _, key := range photoKeys {
+		objectName, err := receiveUpload(r.Context(), conn, gcsClient, key, public)
+		if err != nil {
+			writeUploadError(w, r, err)
+			return
+		}
+		received = append(received, receivedPhoto{objectName: objectName, photoKey: key})
+	}
+
+	// 登録に失敗した場合はアップロード済みの写真を削除する
+	// （直接アップロードされた写真はアップロード枠が残るため、期限切れ後に削除される）
+	var uploaded []string
+	committed := false
+	defer func() {
+		if !committed {
+			for _, objectName := range uploaded {
+				discardPhoto(r.Context(), gcsClient, objectName)
+			}
+		}
+	}()
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "写真", "ファイルの読み取りに失敗しました")
+			return
+		}
+		objectName, err := uploadPhoto(r.Context(), gcsClient, file, header, public)
+		file.Close()
+		if err != nil {
+			writeServerError(w, r, err, "写真", "写真のアップロードに失敗しました")
+			return
+		}
+		uploaded = append(uploaded, objectName)
+	}
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	// 同じ料理への写真の登録が同時に行われても上限を超えないよう、料理の行をロックしてから枚数を数え直す
+	err = tx.QueryRow(r.Context(),
+		`SELECT (SELECT count(*) FROM dish_photos WHERE dish_id = d.id)
+		 FROM dishes d WHERE d.id = $1 AND d.deleted_at IS NULL FOR UPDATE`,
+		dishID,
+	).Scan(&count)
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "写真の取得に失敗しました")
+		return
+	}
+	if count+len(headers)+len(photoKeys) > maxDishPhotos {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", fmt.Sprintf("写真は1つの料理につき%d枚まで登録できます", maxDishPhotos))
+		return
+	}
+
+	// 直接アップロードした写真のアップロード枠を使用済みにする
+	for _, key := range photoKeys {
+		if err := consumeUpload(r.Context(), tx, key); err != nil {
+			writeUploadError(w, r, err)
+			return
+		}
+	}
+
+	objectNames := append([]string{}, uploaded...)
+	for _, photo := range received {
+		objectNames = append(objectNames, photo.objectName)
+	}
+	var firstID string
+	for i, objectName := range objectNames {
+		var id string
+		err := tx.QueryRow(r.Context(),
+			`INSERT INTO dish_photos (dish_id, object_name, sort_order, is_public)
+			 VALUES ($1, $2, (SELECT COALESCE(max(sort_order) + 1, 0) FROM dish_photos WHERE dish_id = $1), $3)
+			 RETURNING id`,
+			dishID, objectName, public,
+		).Scan(&id)
+		if err != nil {
+			writeServerError(w, r, err, "データベース", "写真の登録に失敗しました")
+			return
+		}
+		if i == 0 {
+			firstID = id
+		}
+	}
+
+	// 代表写真がない料理は最初の写真を代表写真にする（非公開の写真は代表写真にしない）
+	if !cover && public {
+		var hasCover bool
+		if err := tx.QueryRow(r.Context(), `SELECT EXISTS (SELECT 1 FROM dish_photos WHERE dish_id = $1 AND is_cover)`, dishID).Scan(&hasCover); err != nil {
+			writeServerError(w, r, err, "データベース", "写真の登録に失敗しました")
+			return
+		}
+		cover = !hasCover
+	}
+	if cover {
+		if err := changeCoverPhoto(r.Context(), tx, dishID, firstID, actorFromRequest(r)); err != nil {
+			writeServerError(w, r, err, "データベース", "代表写真の変更に失敗しました")
+			return
+		}
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "写真の登録に失敗しました")
+		return
+	}
+	committed = true
+	for _, photo := range received {
+		releaseUpload(r.Context(), gcsClient, photo)
+	}
+	metrics.PhotosUploaded.WithLabelValues(uploadMethod(false)).Add(float64(len(uploaded)))
+	metrics.PhotosUploaded.WithLabelValues(uploadMethod(true)).Add(float64(len(photoKeys)))
+
+	writeDishPhotos(w, r, conn, dishID, http.StatusCreated)
+}
+
+// 料理写真並び替えハンドラー
+// @Summary 料理写真並び替え
+// @Description 料理のすべての写真IDを表示順に指定して並び替えます
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param body body PhotoOrderRequest true "表示順の写真ID"
+// @Success 200 {array} model.DishPhoto
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/photos/order [put]
+func PutDishPhotoOrder(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	var req PhotoOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return
+	}
+	if validationErrors := validatePhotoOrderRequest(req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	// 指定された写真が料理の写真とちょうど一致する場合のみ並び替える
+	var total, matched int
+	err = tx.QueryRow(r.Context(),
+		`SELECT count(*), count(*) FILTER (WHERE id = ANY($2::uuid[])) FROM dish_photos WHERE dish_id = $1`,
+		dishID, req.PhotoIDs,
+	).Scan(&total, &matched)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "写真の取得に失敗しました")
+		return
+	}
+	if total != len(req.PhotoIDs) || matched != len(req.PhotoIDs) {
+		writeErrorResponse(w, http.StatusBadRequest, "写真ID", "料理のすべての写真IDを1回ずつ指定してください")
+		return
+	}
+
+	_, err = tx.Exec(r.Context(),
+		`UPDATE dish_photos p SET sort_order = o.ord - 1
+		 FROM unnest($2::uuid[]) WITH ORDINALITY AS o (id, ord)
+		 WHERE p.dish_id = $1 AND p.id = o.id`,
+		dishID, req.PhotoIDs,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "写真の並び替えに失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "写真の並び替えに失敗しました")
+		return
+	}
+
+	writeDishPhotos(w, r, conn, dishID, http.StatusOK)
+}
+
+// 代表写真変更ハンドラー
+// @Summary 代表写真変更
+// @Description 指定した写真を料理の代表写真（料理の img）にします。変更は料理の変更履歴に記録されます
+// @Tags photos
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param photoId path string true "写真ID"
+// @Success 200 {array} model.DishPhoto
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/photos/{photoId}/cover [put]
+func PutDishCoverPhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dishID := vars["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	if err := changeCoverPhoto(r.Context(), tx, dishID, vars["photoId"], actorFromRequest(r)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "写真", "指定されたIDの写真が見つかりません")
+			return
+		}
+		if errors.Is(err, errPrivateCoverPhoto) {
+			writeErrorResponse(w, http.StatusBadRequest, "代表写真", "非公開の写真は代表写真にできません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "代表写真の変更に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "代表写真の変更に失敗しました")
+		return
+	}
+
+	writeDishPhotos(w, r, conn, dishID, http.StatusOK)
+}
+
+// 料理写真削除ハンドラー
+// @Summary 料理写真削除
+// @Description 料理の写真を削除します。代表写真を削除した場合は表示順が最初の公開写真を代表写真にします。代表写真にできる公開写真が他にない場合は削除できません
+// @Tags photos
+// @Param id path string true "料理ID"
+// @Param photoId path string true "写真ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/photos/{photoId} [delete]
+func DeleteDishPhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dishID := vars["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	var objectName string
+	var wasCover bool
+	err = tx.QueryRow(r.Context(),
+		`DELETE FROM dish_photos p USING dishes d
+		 WHERE p.id = $2 AND p.dish_id = $1 AND d.id = p.dish_id AND d.deleted_at IS NULL
+		 RETURNING p.object_name, p.is_cover`,
+		dishID, vars["photoId"],
+	).Scan(&objectName, &wasCover)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "写真", "指定されたIDの写真が見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "写真の削除に失敗しました")
+		return
+	}
+
+	if wasCover {
+		var nextID string
+		err := tx.QueryRow(r.Context(),
+			`SELECT id FROM dish_photos WHERE dish_id = $1 AND is_public ORDER BY sort_order, created_at LIMIT 1`,
+			dishID,
+		).Scan(&nextID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusBadRequest, "写真", "料理の最後の公開写真（代表写真）は削除できません")
+			return
+		}
+		if err == nil {
+			err = changeCoverPhoto(r.Context(), tx, dishID, nextID, actorFromRequest(r))
+		}
+		if err != nil {
+			writeServerError(w, r, err, "データベース", "代表写真の変更に失敗しました")
+			return
+		}
+	}
+
+	// 変更履歴の復元で使う写真や、同じ内容の写真（ハッシュ名）を使う料理・セットがある場合は残す
+	var referenced bool
+	err = tx.QueryRow(r.Context(),
+		`SELECT EXISTS (SELECT 1 FROM dish_photos WHERE object_name = $1)
+		     OR EXISTS (SELECT 1 FROM dishes WHERE photo_url = $1)
+		     OR EXISTS (SELECT 1 FROM bundles WHERE photo_url = $1)
+		     OR EXISTS (SELECT 1 FROM dish_revisions WHERE old_values->>'img' = $1 OR new_values->>'img' = $1)`,
+		objectName,
+	).Scan(&referenced)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "写真の削除に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "写真の削除に失敗しました")
+		return
+	}
+
+	if !referenced {
+		if err := gcsClient.DeleteFile(r.Context(), utils.ObjectName(objectName)); err != nil {
+			slog.ErrorContext(r.Context(), "写真ファイルの削除に失敗しました", "object", objectName, "error", err)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeDishPhotos 料理の写真一覧を署名付きURLに変換してレスポンスに書き込む
+func writeDishPhotos(w http.ResponseWriter, r *http.Request, q db.Querier, dishID string, statusCode int) {
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	photos, err := loadDishPhotos(r.Context(), q, gcsClient, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "写真の取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(photos)
+}
+
+// loadDishPhotos 料理の写真を表示順に取得し、画像URLを署名付きURLにする
+func loadDishPhotos(ctx context.Context, q db.Querier, gcsClient *utils.GCSClient, dishID string) ([]model.DishPhoto, error) {
+	rows, err := q.Query(ctx,
+		`SELECT id, dish_id, object_name, sort_order, is_cover, is_public, created_at FROM dish_photos
+		 WHERE dish_id = $1 ORDER BY sort_order, created_at`,
+		dishID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	photos, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.DishPhoto, error) {
+		var p model.DishPhoto
+		err := row.Scan(&p.ID, &p.DishID, &p.URL, &p.SortOrder, &p.IsCover, &p.IsPublic, &p.CreatedAt)
+		return p, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imgs := make([]*string, len(photos))
+	for i := range photos {
+		imgs[i] = &photos[i].URL
+	}
+	if err := signImageURLs(ctx, gcsClient, imgs); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// errPrivateCoverPhoto 非公開の写真を代表写真にしようとした
+var errPrivateCoverPhoto = errors.New("private photo cannot be the cover photo")
+
+// changeCoverPhoto 指定した写真を代表写真にし、料理の photo_url と変更履歴に反映する
+// 写真または料理が見つからない場合は pgx.ErrNoRows、非公開の写真の場合は errPrivateCoverPhoto を返す
+func changeCoverPhoto(ctx context.Context, q db.Querier, dishID, photoID, actor string) error {
+	var current model.Dish
+	if err := scanDish(q.QueryRow(ctx,
+		`SELECT `+dishColumns+` FROM dishes WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, dishID,
+	), &current); err != nil {
+		return err
+	}
+
+	var objectName string
+	var public bool
+	if err := q.QueryRow(ctx,
+		`SELECT object_name, is_public FROM dish_photos WHERE id = $1 AND dish_id = $2`, photoID, dishID,
+	).Scan(&objectName, &public); err != nil {
+		return err
+	}
+	if !public {
+		return errPrivateCoverPhoto
+	}
+
+	// 代表写真は料理ごとに1枚のため、先に現在の代表写真を外す
+	if _, err := q.Exec(ctx, `UPDATE dish_photos SET is_cover = false WHERE dish_id = $1 AND is_cover AND id <> $2`, dishID, photoID); err != nil {
+		return err
+	}
+	if _, err := q.Exec(ctx, `UPDATE dish_photos SET is_cover = true WHERE id = $1`, photoID); err != nil {
+		return err
+	}
+
+	if objectName == current.Img {
+		return nil
+	}
+	if _, err := q.Exec(ctx, `UPDATE dishes SET photo_url = $1 WHERE id = $2`, objectName, dishID); err != nil {
+		return err
+	}
+	updated := current
+	updated.Img = objectName
+	return recordDishRevision(ctx, q, dishID, model.RevisionUpdate, dishSnapshot(current), dishSnapshot(updated), actor)
+}
+
+// syncCoverPhoto 料理の photo_url に設定した写真を代表写真として dish_photos に反映する
+// 同じ写真が登録済みの場合はその写真を代表にし、ない場合は先頭に追加する
+func syncCoverPhoto(ctx context.Context, q db.Querier, dishID, objectName string) error {
+	if _, err := q.Exec(ctx, `UPDATE dish_photos SET is_cover = false WHERE dish_id = $1 AND is_cover AND object_name <> $2`, dishID, objectName); err != nil {
+		return err
+	}
+	if objectName == "" {
+		return nil
+	}
+
+	result, err := q.Exec(ctx, `UPDATE dish_photos SET is_cover = true WHERE dish_id = $1 AND object_name = $2`, dishID, objectName)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	if _, err := q.Exec(ctx, `UPDATE dish_photos SET sort_order = sort_order + 1 WHERE dish_id = $1`, dishID); err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx,
+		`INSERT INTO dish_photos (dish_id, object_name, sort_order, is_cover) VALUES ($1, $2, 0, true)`,
+		dishID, objectName,
+	)
+	return err
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/gorilla/mux"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// 画像配信ハンドラー
+// @Summary 写真の配信
+// @Description 内容のハッシュ名で保存した写真を配信します。内容が変わらないため、ブラウザやCDNで無期限にキャッシュできます（IMAGE_PUBLIC_BASE_URL にこのパスを指定して使う）
+// @Tags photos
+// @Produce image/jpeg,image/png,image/webp
+// @Param name path string true "写真のファイル名（<sha256>.<拡張子>）"
+// @Success 200 {file} binary
+// @Success 304 "変更なし"
+// @Failure 404 {object} ErrorResponse
+// @Router /images/{name} [get]
+func GetImage(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	// 署名付きURLで配信する非公開の写真は返さない
+	objectName := hashedImagePrefix + name
+	if !isHashedImage(objectName) {
+		writeErrorResponse(w, http.StatusNotFound, "写真", "指定された写真が見つかりません")
+		return
+	}
+
+	// ファイル名が内容のハッシュなので、ETag が一致すれば内容も同じ
+	etag := `"` + strings.TrimSuffix(name, getFileExtension(name)) + `"`
+	if etagMatches(r.Header.Values("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", utils.ImmutableCacheControl)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	rc, err := gcsClient.OpenFile(r.Context(), objectName)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			writeErrorResponse(w, http.StatusNotFound, "写真", "指定された写真が見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "写真", "写真の取得に失敗しました")
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", rc.Attrs.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(rc.Attrs.Size, 10))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", utils.ImmutableCacheControl)
+	if _, err := io.Copy(w, rc); err != nil {
+		// ヘッダーは送信済みのためエラーレスポンスは返せない
+		slog.WarnContext(r.Context(), "写真の送信に失敗しました", "object", objectName, "error", err)
+	}
+}
+
+// etagMatches If-None-Match のいずれかの ETag が etag と一致するかどうか
+// ヘッダーはカンマ区切りの一覧で、弱い比較のため W/ 付きの ETag も一致とみなす
+func etagMatches(headers []string, etag string) bool {
+	for _, header := range headers {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,601 @@
+package admin
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/metrics"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/utils"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+const (
+	// maxImportRows 一度に取り込める最大行数
+	maxImportRows = 1000
+	// maxImportPhotoSize 取り込む写真1枚あたりの最大サイズ
+	maxImportPhotoSize = 10 << 20
+	// maxImportPhotoFiles 写真のZIPファイルに含められる最大ファイル数
+	maxImportPhotoFiles = maxImportRows
+	// maxImportPhotosTotalSize 写真のZIPファイルを展開した合計の最大サイズ（展開した写真はメモリに読み込むため）
+	maxImportPhotosTotalSize = 200 << 20
+)
+
+// importColumns 取り込みファイルの列（name_ja, name_en, price, image は必須、続けて詳細情報の列）
+var importColumns = append([]string{"name_ja", "name_en", "price", "image", "category", "reading", "allergens"}, strings.Split(dishDetailColumns, ", ")...)
+
+// ImportRowError 取り込みファイルの行ごとのエラー
+type ImportRowError struct {
+	Row    int               `json:"row"`    // 行番号（ヘッダー行を1行目とする）
+	Errors []ValidationError `json:"errors"` // エラー内容
+}
+
+// ImportedDish 取り込まれた（ドライラン時は取り込み予定の）料理
+type ImportedDish struct {
+	Row    int    `json:"row"`          // 行番号
+	ID     string `json:"id,omitempty"` // 登録された料理ID（ドライラン時は空）
+	NameJa string `json:"nameJa"`       // 日本語名
+	NameEn string `json:"nameEn"`       // 英語名
+	Price  int    `json:"price"`        // 価格
+}
+
+// ImportResult 一括取り込みの結果
+type ImportResult struct {
+	DryRun   bool             `json:"dryRun"`   // ドライランかどうか
+	Total    int              `json:"total"`    // データ行数
+	Imported int              `json:"imported"` // 取り込まれた件数（ドライラン・エラー時は0）
+	Dishes   []ImportedDish   `json:"dishes"`   // 取り込まれた料理
+	Errors   []ImportRowError `json:"errors"`   // 行ごとのエラー
+}
+
+// importRow 検証済みの取り込み行
+type importRow struct {
+	line     int
+	request  CreateDishRequest
+	image    string
+	photo    []byte
+	photoKey string
+}
+
+// 料理一括取り込みハンドラー
+// @Summary 料理一括取り込み
+// @Description CSVファイル（UTF-8またはShift_JIS）またはエクスポートしたJSONファイルと写真のZIPファイルから料理を一括登録します。1行でもエラーがあれば何も登録しません
+// @Tags dishes
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "料理一覧のCSVファイル（name_ja, name_en, price, image, category, reading, allergens, description_ja など詳細情報の列）またはJSONファイル"
+// @Param photos formData file false "image列のファイル名に対応する写真をまとめたZIPファイル（保存済みの写真のみの場合は省略可。1000枚・展開後の合計200MBまで、同じファイル名の写真は1枚まで）"
+// @Param encoding formData string false "CSVの文字コード（auto, utf-8, shift_jis。省略時はauto）"
+// @Param dryRun formData boolean false "trueの場合は検証のみ行い登録しない"
+// @Success 200 {object} ImportResult "ドライランの結果"
+// @Success 201 {object} ImportResult "取り込み結果"
+// @Failure 400 {object} ImportResult
+// @Router /dishes/import [post]
+func ImportDishes(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(64 << 20); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "フォーム", "フォームデータの解析に失敗しました")
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.FormValue("dryRun"))
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ファイル", "取り込みファイルが選択されていません")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ファイル", "ファイルの読み取りに失敗しました")
+		return
+	}
+
+	records, err := readImportRecords(data, r.FormValue("encoding"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ファイル", err.Error())
+		return
+	}
+
+	photos, err := readImportPhotos(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", err.Error())
+		return
+	}
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	// ZIPファイルに含まれない写真は、保存済みの料理の写真のファイル名として探す
+	var missing []string
+	for _, record := range records {
+		if image := record["image"]; image != "" {
+			if _, ok := photos[path.Base(image)]; !ok {
+				missing = append(missing, image)
+			}
+		}
+	}
+	storedPhotos, err := loadStoredPhotos(r.Context(), missing)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "保存済みの写真の確認に失敗しました")
+		return
+	}
+
+	rows, rowErrors := validateImportRecords(records, photos, storedPhotos)
+
+	result := ImportResult{
+		DryRun: dryRun,
+		Total:  len(records),
+		Dishes: []ImportedDish{},
+		Errors: rowErrors,
+	}
+	if result.Errors == nil {
+		result.Errors = []ImportRowError{}
+	}
+	for _, row := range rows {
+		result.Dishes = append(result.Dishes, ImportedDish{
+			Row:    row.line,
+			NameJa: row.request.NameJa,
+			NameEn: row.request.NameEn,
+			Price:  row.request.Price,
+		})
+	}
+
+	if len(rowErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if dryRun {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	ids, err := commitImport(r.Context(), gcsClient, rows, actorFromRequest(r))
+	if err != nil {
+		writeServerError(w, r, err, "取り込み", "料理の一括登録に失敗しました")
+		return
+	}
+	for i := range result.Dishes {
+		result.Dishes[i].ID = ids[i]
+	}
+	result.Imported = len(ids)
+	metrics.DishesCreated.WithLabelValues("import").Add(float64(len(ids)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// readImportRecords 取り込みファイルを列名をキーとしたレコードの一覧に変換する
+func readImportRecords(data []byte, encoding string) ([]map[string]string, error) {
+	text, err := decodeImportText(data, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	// エクスポートしたJSONファイルもそのまま取り込めるようにする
+	if strings.HasPrefix(strings.TrimSpace(text), "[") {
+		return readImportJSON(text)
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSVの解析に失敗しました: %v", err)
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("ヘッダー行がありません")
+	}
+
+	header := make([]string, len(lines[0]))
+	found := map[string]bool{}
+	for i, name := range lines[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		found[header[i]] = true
+	}
+	for _, required := range importColumns[:4] {
+		if !found[required] {
+			return nil, fmt.Errorf("必須の列 %s がありません", required)
+		}
+	}
+
+	var records []map[string]string
+	for _, line := range lines[1:] {
+		record := map[string]string{}
+		empty := true
+		for i, value := range line {
+			if i < len(header) {
+				record[header[i]] = strings.TrimSpace(value)
+				if record[header[i]] != "" {
+					empty = false
+				}
+			}
+		}
+		// Excelで出力した際の末尾の空行は無視する
+		if empty {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return checkImportRecordCount(records)
+}
+
+// readImportJSON エクスポートしたJSON形式のファイルをCSVと同じレコードに変換する
+func readImportJSON(text string) ([]map[string]string, error) {
+	var items []DishExportRecord
+	if err := json.Unmarshal([]byte(text), &items); err != nil {
+		return nil, fmt.Errorf("JSONの解析に失敗しました: %v", err)
+	}
+
+	detailColumns := strings.Split(dishDetailColumns, ", ")
+	records := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		record := map[string]string{
+			"name_ja":  strings.TrimSpace(item.NameJa),
+			"name_en":  strings.TrimSpace(item.NameEn),
+			"price":    strconv.Itoa(item.Price),
+			"image":    strings.TrimSpace(item.Image),
+			"category": strings.TrimSpace(item.Category),
+			"reading":  strings.TrimSpace(item.Reading),
+			// CSVと同じくカンマ区切りにしておく
+			"allergens": strings.Join(item.Allergens, ","),
+		}
+		for i, v := range dishDetailValues(item.DishDetails) {
+			record[detailColumns[i]] = v
+		}
+		records = append(records, record)
+	}
+
+	return checkImportRecordCount(records)
+}
+
+// importDetailField 取り込み行から詳細情報の項目を取得する関数を作成する（フォーム項目名を列名に対応させる）
+func importDetailField(record map[string]string) func(name string) (string, bool) {
+	detailColumns := strings.Split(dishDetailColumns, ", ")
+	return func(name string) (string, bool) {
+		i := slices.Index(dishDetailFormFields, name)
+		if i < 0 {
+			return "", false
+		}
+		v, ok := record[detailColumns[i]]
+		return v, ok
+	}
+}
+
+// checkImportRecordCount 取り込む行数を確認する
+func checkImportRecordCount(records []map[string]string) ([]map[string]string, error) {
+	if len(records) == 0 {
+		return nil, errors.New("取り込むデータがありません")
+	}
+	if len(records) > maxImportRows {
+		return nil, fmt.Errorf("一度に取り込めるのは%d行までです", maxImportRows)
+	}
+	return records, nil
+}
+
+// decodeImportText 文字コードを判定してUTF-8の文字列に変換する
+// auto の場合、UTF-8として不正なバイト列を含むファイルはShift_JISとして扱う
+func decodeImportText(data []byte, encoding string) (string, error) {
+	switch strings.ToLower(strings.ReplaceAll(encoding, "-", "_")) {
+	case "", "auto":
+		if utf8.Valid(data) {
+			return strings.TrimPrefix(string(data), "\ufeff"), nil
+		}
+		return decodeShiftJIS(data)
+	case "utf_8", "utf8":
+		if !utf8.Valid(data) {
+			return "", errors.New("UTF-8として読み取れない文字が含まれています")
+		}
+		return strings.TrimPrefix(string(data), "\ufeff"), nil
+	case "shift_jis", "sjis", "cp932":
+		return decodeShiftJIS(data)
+	default:
+		return "", fmt.Errorf("対応していない文字コードです: %s", encoding)
+	}
+}
+
+// decodeShiftJIS Shift_JISのバイト列をUTF-8の文字列に変換する
+func decodeShiftJIS(data []byte) (string, error) {
+	decoded, _, err := transform.Bytes(japanese.ShiftJIS.NewDecoder(), data)
+	if err != nil {
+		return "", errors.New("Shift_JISとして読み取れない文字が含まれています")
+	}
+	return string(decoded), nil
+}
+
+// readImportPhotos 写真のZIPファイルを読み込み、ファイル名から中身へのマップを返す
+// ファイル数・展開後の合計サイズが上限を超える場合や、フォルダが違っても同じファイル名の写真がある場合はエラーにする
+func readImportPhotos(r *http.Request) (map[string][]byte, error) {
+	file, header, err := r.FormFile("photos")
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return map[string][]byte{}, nil
+		}
+		return nil, errors.New("写真ファイルの読み取りに失敗しました")
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, header.Size)
+	if err != nil {
+		return nil, errors.New("ZIPファイルの解析に失敗しました")
+	}
+
+	var files []*zip.File
+	var declaredSize uint64
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		files = append(files, f)
+		declaredSize += f.UncompressedSize64
+	}
+	if len(files) > maxImportPhotoFiles {
+		return nil, fmt.Errorf("ZIPファイルに含められる写真は%d枚までです", maxImportPhotoFiles)
+	}
+	if declaredSize > maxImportPhotosTotalSize {
+		return nil, fmt.Errorf("ZIPファイルの写真は合計%dMBまでです", maxImportPhotosTotalSize>>20)
+	}
+
+	photos := map[string][]byte{}
+	var totalSize int
+	for _, f := range files {
+
+		// Windowsで作成したZIPはファイル名がShift_JISの場合がある
+		name := f.Name
+		if !utf8.ValidString(name) {
+			if decoded, err := decodeShiftJIS([]byte(name)); err == nil {
+				name = decoded
+			}
+		}
+		name = path.Base(name)
+		if _, ok := photos[name]; ok {
+			return nil, fmt.Errorf("%s が複数含まれています（フォルダが違っても同じファイル名は使えません）", name)
+		}
+
+		if f.UncompressedSize64 > maxImportPhotoSize {
+			return nil, fmt.Errorf("%s のサイズが大きすぎます（10MBまで）", name)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%s の読み取りに失敗しました", name)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxImportPhotoSize+1))
+		rc.Close()
+		if err != nil || len(content) > maxImportPhotoSize {
+			return nil, fmt.Errorf("%s の読み取りに失敗しました", name)
+		}
+		// ZIPに記録されたサイズは偽装できるため、実際に展開したサイズでも確認する
+		if totalSize += len(content); totalSize > maxImportPhotosTotalSize {
+			return nil, fmt.Errorf("ZIPファイルの写真は合計%dMBまでです", maxImportPhotosTotalSize>>20)
+		}
+
+		photos[name] = content
+	}
+
+	return photos, nil
+}
+
+// loadStoredPhotos names のうち、料理の写真として保存済みのもの（エクスポートしたファイルの再取り込み用）を返す
+// 他の料理で使われていない任意のファイルを料理の写真にできないよう、料理の代表写真・公開写真として登録済みのものに限る
+func loadStoredPhotos(ctx context.Context, names []string) (map[string]bool, error) {
+	stored := map[string]bool{}
+	if len(names) == 0 {
+		return stored, nil
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx,
+		`SELECT DISTINCT name FROM unnest($1::text[]) AS name
+		 WHERE EXISTS (SELECT 1 FROM dishes WHERE photo_url = name)
+		    OR EXISTS (SELECT 1 FROM dish_photos WHERE object_name = name AND is_public)`,
+		names,
+	)
+	if err != nil {
+		return nil, err
+	}
+	found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range found {
+		stored[name] = true
+	}
+	return stored, nil
+}
+
+// validateImportRecords 各行に料理作成時と同じバリデーションを行う
+// ZIPファイルに含まれない写真は、エクスポートしたファイルの再取り込みとして保存済みの写真（storedPhotos）を探す
+func validateImportRecords(records []map[string]string, photos map[string][]byte, storedPhotos map[string]bool) ([]importRow, []ImportRowError) {
+	var rows []importRow
+	var rowErrors []ImportRowError
+
+	for i, record := range records {
+		line := i + 2 // ヘッダー行が1行目
+
+		var errs []ValidationError
+		price := 0
+		if p := record["price"]; p != "" {
+			parsed, err := strconv.Atoi(strings.ReplaceAll(p, ",", ""))
+			if err != nil {
+				errs = append(errs, ValidationError{Field: "価格", Message: "価格が不正です"})
+			} else {
+				price = parsed
+			}
+		}
+
+		details, detailErrors := parseDishDetails(importDetailField(record))
+		errs = append(errs, detailErrors...)
+
+		req := CreateDishRequest{
+			NameJa:    record["name_ja"],
+			NameEn:    record["name_en"],
+			Reading:   record["reading"],
+			Price:     price,
+			Category:  record["category"],
+			Allergens: parseAllergens(record["allergens"]),
+
+			DishDetailsRequest: details,
+		}
+		errs = append(errs, validateCreateDishRequest(req)...)
+
+		image := record["image"]
+		var photo []byte
+		switch {
+		case image == "":
+			errs = append(errs, ValidationError{Field: "写真", Message: "写真ファイル名が指定されていません"})
+		case !isValidImageFormat(image):
+			errs = append(errs, ValidationError{Field: "写真", Message: "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています"})
+		default:
+			var ok bool
+			if photo, ok = photos[path.Base(image)]; !ok && !storedPhotos[image] {
+				errs = append(errs, ValidationError{Field: "写真", Message: fmt.Sprintf("%s がZIPファイルに含まれておらず、保存済みの料理の写真でもありません", image)})
+			}
+		}
+
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, ImportRowError{Row: line, Errors: errs})
+			continue
+		}
+
+		rows = append(rows, importRow{line: line, request: req, image: image, photo: photo})
+	}
+
+	return rows, rowErrors
+}
+
+// commitImport 写真をアップロードし、全行を1つのトランザクションで登録する
+// 登録に失敗した場合はアップロード済みの写真を削除する
+func commitImport(ctx context.Context, gcsClient *utils.GCSClient, rows []importRow, actor string) ([]string, error) {
+	uploaded := []string{}
+	cleanup := func() {
+		for _, key := range uploaded {
+			discardPhoto(ctx, gcsClient, key)
+		}
+	}
+
+	// 同じ写真を複数行で使う場合は1回だけアップロードする
+	keys := map[string]string{}
+	for i := range rows {
+		// 保存済みの写真はそのまま使う
+		if rows[i].photo == nil {
+			rows[i].photoKey = rows[i].image
+			continue
+		}
+
+		name := path.Base(rows[i].image)
+		if key, ok := keys[name]; ok {
+			rows[i].photoKey = key
+			continue
+		}
+
+		key, err := storePhoto(ctx, gcsClient, rows[i].photo, name, true)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		uploaded = append(uploaded, key)
+		keys[name] = key
+		rows[i].photoKey = key
+	}
+
+	ids, err := insertImportedDishes(ctx, rows, actor)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// insertImportedDishes 取り込み行を1つのトランザクションで登録する
+func insertImportedDishes(ctx context.Context, rows []importRow, actor string) ([]string, error) {
+	conn, err := db.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		d := model.Dish{
+			NameJa:    row.request.NameJa,
+			NameEn:    row.request.NameEn,
+			Reading:   row.request.Reading,
+			Price:     row.request.Price,
+			Img:       row.photoKey,
+			Category:  row.request.Category,
+			Allergens: row.request.Allergens,
+
+			DishDetails: dishDetails(row.request.DishDetailsRequest),
+		}
+
+		var id string
+		err := tx.QueryRow(ctx,
+			`INSERT INTO dishes (name_ja, name_en, reading, search_text, price, photo_url, category, allergens, `+dishDetailColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, `+dishDetailPlaceholders(9)+`) RETURNING id`,
+			append([]any{d.NameJa, d.NameEn, d.Reading, dishSearchText(d.NameJa, d.NameEn, d.Reading), d.Price, d.Img, d.Category, d.Allergens}, dishDetailArgs(d.DishDetails)...)...,
+		).Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := syncCoverPhoto(ctx, tx, id, d.Img); err != nil {
+			return nil, err
+		}
+		if err := recordDishRevision(ctx, tx, id, model.RevisionCreate, nil, dishSnapshot(d), actor); err != nil {
+			return nil, err
+		}
+
+		// 他の言語の料理名の下書きを作成する（英語名が空欄の場合は英語も。失敗しても取り込みは取り消さない）
+		d.ID = id
+		if err := suggestTranslations(ctx, tx, d); err != nil {
+			slog.ErrorContext(ctx, "翻訳の下書きの作成に失敗しました", "dish_id", id, "error", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
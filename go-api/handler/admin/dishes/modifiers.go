@@ -0,0 +1,352 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/model"
+)
+
+// オプショングループ一覧取得ハンドラー
+// @Summary オプショングループ一覧取得
+// @Description 料理に紐づくオプショングループと選択肢の一覧を取得します
+// @Tags modifiers
+// @Produce json
+// @Param id path string true "料理ID"
+// @Success 200 {array} model.ModifierGroup
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/modifier-groups [get]
+func GetModifierGroups(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	groups, err := loadModifierGroups(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "オプショングループの取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// オプショングループ作成ハンドラー
+// @Summary オプショングループ作成
+// @Description 料理にオプショングループ（サイズ、辛さ、トッピングなど）を選択肢とともに登録します
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param body body ModifierGroupRequest true "オプショングループ"
+// @Success 201 {object} model.ModifierGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/modifier-groups [post]
+func PostModifierGroup(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	req, ok := decodeModifierGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	var groupID string
+	err = tx.QueryRow(r.Context(),
+		`INSERT INTO modifier_groups (dish_id, name_ja, name_en, selection_type, min_choices, max_choices, required, sort_order)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		dishID, req.NameJa, req.NameEn, req.SelectionType, req.MinChoices, req.MaxChoices, req.Required, req.SortOrder,
+	).Scan(&groupID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "オプショングループの登録に失敗しました")
+		return
+	}
+
+	if err := insertModifierOptions(r.Context(), tx, groupID, req.Options); err != nil {
+		writeServerError(w, r, err, "データベース", "選択肢の登録に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "オプショングループの登録に失敗しました")
+		return
+	}
+
+	group, err := loadModifierGroup(r.Context(), conn, dishID, groupID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "登録データの取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+// オプショングループ更新ハンドラー
+// @Summary オプショングループ更新
+// @Description オプショングループを更新します（選択肢はリクエストの内容で置き換えられます）
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param groupId path string true "オプショングループID"
+// @Param body body ModifierGroupRequest true "オプショングループ"
+// @Success 200 {object} model.ModifierGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/modifier-groups/{groupId} [put]
+func PutModifierGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dishID := vars["id"]
+	groupID := vars["groupId"]
+
+	req, ok := decodeModifierGroupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	result, err := tx.Exec(r.Context(),
+		`UPDATE modifier_groups
+		 SET name_ja = $1, name_en = $2, selection_type = $3, min_choices = $4, max_choices = $5, required = $6, sort_order = $7
+		 WHERE id = $8 AND dish_id = $9`,
+		req.NameJa, req.NameEn, req.SelectionType, req.MinChoices, req.MaxChoices, req.Required, req.SortOrder, groupID, dishID,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "オプショングループの更新に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "オプショングループ", "指定されたIDのオプショングループが見つかりません")
+		return
+	}
+
+	// 選択肢は全件入れ替える
+	if _, err := tx.Exec(r.Context(), `DELETE FROM modifier_options WHERE group_id = $1`, groupID); err != nil {
+		writeServerError(w, r, err, "データベース", "選択肢の更新に失敗しました")
+		return
+	}
+	if err := insertModifierOptions(r.Context(), tx, groupID, req.Options); err != nil {
+		writeServerError(w, r, err, "データベース", "選択肢の更新に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "オプショングループの更新に失敗しました")
+		return
+	}
+
+	group, err := loadModifierGroup(r.Context(), conn, dishID, groupID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "更新データの取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// オプショングループ削除ハンドラー
+// @Summary オプショングループ削除
+// @Description オプショングループと選択肢を削除します
+// @Tags modifiers
+// @Param id path string true "料理ID"
+// @Param groupId path string true "オプショングループID"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/modifier-groups/{groupId} [delete]
+func DeleteModifierGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dishID := vars["id"]
+	groupID := vars["groupId"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	result, err := conn.Exec(r.Context(),
+		`DELETE FROM modifier_groups WHERE id = $1 AND dish_id = $2`,
+		groupID, dishID,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "オプショングループの削除に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "オプショングループ", "指定されたIDのオプショングループが見つかりません")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeModifierGroupRequest リクエストボディを読み取りバリデーションする
+// エラー時はレスポンスを書き込み false を返す
+func decodeModifierGroupRequest(w http.ResponseWriter, r *http.Request) (ModifierGroupRequest, bool) {
+	var req ModifierGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return req, false
+	}
+
+	if validationErrors := validateModifierGroupRequest(req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return req, false
+	}
+
+	return req, true
+}
+
+// insertModifierOptions 選択肢をまとめて登録する
+func insertModifierOptions(ctx context.Context, q db.Querier, groupID string, options []ModifierOptionRequest) error {
+	for _, opt := range options {
+		_, err := q.Exec(ctx,
+			`INSERT INTO modifier_options (group_id, name_ja, name_en, price_delta, sort_order) VALUES ($1, $2, $3, $4, $5)`,
+			groupID, opt.NameJa, opt.NameEn, opt.PriceDelta, opt.SortOrder,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dishExists 料理が存在するか確認する（削除済みの料理は存在しないものとして扱う）
+func dishExists(ctx context.Context, q db.Querier, dishID string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM dishes WHERE id = $1 AND deleted_at IS NULL)`, dishID).Scan(&exists)
+	return exists, err
+}
+
+// loadModifierGroups 料理に紐づくオプショングループを選択肢付きで取得する
+func loadModifierGroups(ctx context.Context, q db.Querier, dishID string) ([]model.ModifierGroup, error) {
+	rows, err := q.Query(ctx,
+		`SELECT id, dish_id, name_ja, name_en, selection_type, min_choices, max_choices, required, sort_order
+		 FROM modifier_groups WHERE dish_id = $1 ORDER BY sort_order, created_at`,
+		dishID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []model.ModifierGroup{}
+	index := map[string]int{}
+	for rows.Next() {
+		var g model.ModifierGroup
+		if err := rows.Scan(&g.ID, &g.DishID, &g.NameJa, &g.NameEn, &g.SelectionType, &g.MinChoices, &g.MaxChoices, &g.Required, &g.SortOrder); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		g.Options = []model.ModifierOption{}
+		index[g.ID] = len(groups)
+		groups = append(groups, g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	rows, err = q.Query(ctx,
+		`SELECT o.id, o.group_id, o.name_ja, o.name_en, o.price_delta, o.sort_order
+		 FROM modifier_options o JOIN modifier_groups g ON g.id = o.group_id
+		 WHERE g.dish_id = $1 ORDER BY o.sort_order, o.name_ja`,
+		dishID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o model.ModifierOption
+		var groupID string
+		if err := rows.Scan(&o.ID, &groupID, &o.NameJa, &o.NameEn, &o.PriceDelta, &o.SortOrder); err != nil {
+			return nil, err
+		}
+		if i, ok := index[groupID]; ok {
+			groups[i].Options = append(groups[i].Options, o)
+		}
+	}
+
+	return groups, rows.Err()
+}
+
+// loadModifierGroup オプショングループを1件取得する
+func loadModifierGroup(ctx context.Context, q db.Querier, dishID, groupID string) (model.ModifierGroup, error) {
+	groups, err := loadModifierGroups(ctx, q, dishID)
+	if err != nil {
+		return model.ModifierGroup{}, err
+	}
+	for _, g := range groups {
+		if g.ID == groupID {
+			return g, nil
+		}
+	}
+	return model.ModifierGroup{}, errors.New("modifier group not found")
+}
@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"regexp"
+	"strings"
+
+	"github.com/smilemasa/go-api/config"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// hashedImagePrefix 内容のハッシュ名で保存した写真のオブジェクト名の接頭辞
+const hashedImagePrefix = "images/"
+
+// hashedImageNamePattern ハッシュ名の写真のファイル名（接頭辞を除く）
+var hashedImageNamePattern = regexp.MustCompile(`^[0-9a-f]{64}\.(jpg|jpeg|png|webp)$`)
+
+// uploadPhoto 写真ファイルをGCSにアップロードし、保存したオブジェクト名を返す
+// ファイル形式のチェックは呼び出し側で isValidImageFormat を使って行う
+func uploadPhoto(ctx context.Context, gcsClient *utils.GCSClient, file multipart.File, header *multipart.FileHeader, public bool) (string, error) {
+	fileBytes, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	return storePhoto(ctx, gcsClient, fileBytes, header.Filename, public)
+}
+
+// useHashedKey 写真を内容のハッシュ名で保存するかどうか
+// ハッシュ名の写真は署名なしで配信されるため、スタッフの顔写真などの非公開の写真（public が false）は対象にしない
+func useHashedKey(public bool) bool {
+	return public && config.Get().Images.ContentHashedKeys
+}
+
+// storePhoto 写真をGCSに保存し、オブジェクト名を返す
+// IMAGE_CONTENT_HASHED_KEYS が有効な場合、公開する写真は内容のハッシュ名で保存し、同じ内容の写真は1つにまとめる
+func storePhoto(ctx context.Context, gcsClient *utils.GCSClient, data []byte, filename string, public bool) (string, error) {
+	if !useHashedKey(public) {
+		objectName := generateSecureFileName(filename)
+		if err := gcsClient.UploadFile(ctx, objectName, data, getContentType(filename)); err != nil {
+			return "", err
+		}
+		return objectName, nil
+	}
+
+	sum := sha256.Sum256(data)
+	objectName := hashedImagePrefix + hex.EncodeToString(sum[:]) + getFileExtension(filename)
+	if err := gcsClient.UploadImmutableFile(ctx, objectName, data, getContentType(filename)); err != nil {
+		return "", err
+	}
+	return objectName, nil
+}
+
+// storeUploadedPhoto 直接アップロードされた写真（アップロード枠のキー）を保存先のオブジェクト名にする
+// 公開する写真をハッシュ名で保存する場合は内容のハッシュ名にコピーする（元のファイルは登録後に releaseUpload で削除する）
+func storeUploadedPhoto(ctx context.Context, gcsClient *utils.GCSClient, photoKey string, public bool) (string, error) {
+	if !useHashedKey(public) {
+		return photoKey, nil
+	}
+
+	rc, err := gcsClient.OpenFile(ctx, photoKey)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+
+	objectName := hashedImagePrefix + hex.EncodeToString(h.Sum(nil)) + getFileExtension(photoKey)
+	if err := gcsClient.CopyImmutableFile(ctx, photoKey, objectName, rc.Attrs.ContentType); err != nil {
+		return "", err
+	}
+	return objectName, nil
+}
+
+// isHashedImage 内容のハッシュ名で保存した写真かどうか
+func isHashedImage(objectName string) bool {
+	name, ok := strings.CutPrefix(objectName, hashedImagePrefix)
+	return ok && hashedImageNamePattern.MatchString(name)
+}
+
+// discardPhoto 登録に失敗した写真を削除する
+// ハッシュ名の写真は同じ内容の写真を他の料理で使っている可能性があるため残す
+func discardPhoto(ctx context.Context, gcsClient *utils.GCSClient, objectName string) {
+	if isHashedImage(objectName) {
+		return
+	}
+	gcsClient.DeleteFile(ctx, objectName)
+}
+
+// publicImageURL ハッシュ名の写真を署名なしで配信するURLを返す（配信できない場合は false）
+// 非公開の写真はハッシュ名で保存しないため、常に署名付きURLになる
+func publicImageURL(objectName string) (string, bool) {
+	baseURL := config.Get().Images.PublicBaseURL
+	if baseURL == "" || !isHashedImage(objectName) {
+		return "", false
+	}
+	return baseURL + "/" + strings.TrimPrefix(objectName, hashedImagePrefix), true
+}
+
+// getContentType ファイル名の拡張子からContent-Typeを判定
+func getContentType(filename string) string {
+	switch getFileExtension(filename) {
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
+// signImageURL DBに保存された画像パスから署名付きURLを生成する
+func signImageURL(ctx context.Context, gcsClient *utils.GCSClient, img string) (string, error) {
+	if img == "" {
+		return "", nil
+	}
+
+	objectName := utils.ObjectName(img)
+	if url, ok := publicImageURL(objectName); ok {
+		return url, nil
+	}
+
+	// 有効期限が十分に残っていればキャッシュした署名付きURLを使う
+	return gcsClient.SignedDownloadURL(ctx, objectName)
+}
+
+// signImageURLs DBに保存された複数の画像パスをまとめて署名付きURLに置き換える
+// 一覧表示で1件ずつ署名すると時間がかかるため、キャッシュにないURLは並行して署名する
+func signImageURLs(ctx context.Context, gcsClient *utils.GCSClient, imgs []*string) error {
+	objectNames := make([]string, 0, len(imgs))
+	for _, img := range imgs {
+		if *img == "" {
+			continue
+		}
+		if url, ok := publicImageURL(utils.ObjectName(*img)); ok {
+			*img = url
+			continue
+		}
+		objectNames = append(objectNames, utils.ObjectName(*img))
+	}
+
+	urls, err := gcsClient.SignedDownloadURLs(ctx, objectNames)
+	if err != nil {
+		return err
+	}
+	for _, img := range imgs {
+		if url, ok := urls[utils.ObjectName(*img)]; ok {
+			*img = url
+		}
+	}
+	return nil
+}
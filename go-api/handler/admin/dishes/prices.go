@@ -0,0 +1,416 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/model"
+)
+
+// 価格変更予約一覧取得ハンドラー
+// @Summary 価格変更予約一覧取得
+// @Description 料理の価格変更予約（適用済みを含む）を適用開始日時の順に取得します。価格を直接変更した場合や変更履歴から復元した場合も、その時点から適用する価格変更として記録されます
+// @Tags prices
+// @Produce json
+// @Param id path string true "料理ID"
+// @Success 200 {array} model.PriceChange
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/price-changes [get]
+func GetPriceChanges(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	rows, err := conn.Query(r.Context(),
+		`SELECT id, dish_id, price, effective_at FROM price_changes WHERE dish_id = $1 ORDER BY effective_at`,
+		dishID,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格変更予約の取得に失敗しました")
+		return
+	}
+	changes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PriceChange, error) {
+		var c model.PriceChange
+		err := row.Scan(&c.ID, &c.DishID, &c.Price, &c.EffectiveAt)
+		return c, err
+	})
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格変更予約の取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+// 価格変更予約ハンドラー
+// @Summary 価格変更予約
+// @Description 指定日時に料理の価格を変更する予約を登録します
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param body body PriceChangeRequest true "価格変更"
+// @Success 201 {object} model.PriceChange
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/price-changes [post]
+func PostPriceChange(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	var req PriceChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return
+	}
+
+	if validationErrors := validatePriceChangeRequest(req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	change := model.PriceChange{DishID: dishID, Price: req.Price}
+	err = conn.QueryRow(r.Context(),
+		`INSERT INTO price_changes (dish_id, price, effective_at) VALUES ($1, $2, $3) RETURNING id, effective_at`,
+		dishID, req.Price, req.EffectiveAt,
+	).Scan(&change.ID, &change.EffectiveAt)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格変更予約の登録に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(change)
+}
+
+// 価格変更予約削除ハンドラー
+// @Summary 価格変更予約削除
+// @Description 価格変更予約を取り消します
+// @Tags prices
+// @Param id path string true "料理ID"
+// @Param changeId path string true "価格変更ID"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/price-changes/{changeId} [delete]
+func DeletePriceChange(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	result, err := conn.Exec(r.Context(),
+		`DELETE FROM price_changes WHERE id = $1 AND dish_id = $2`,
+		vars["changeId"], vars["id"],
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格変更予約の削除に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "価格変更", "指定されたIDの価格変更予約が見つかりません")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 価格ルール一覧取得ハンドラー
+// @Summary 価格ルール一覧取得
+// @Description 曜日・時間帯の価格ルール（ハッピーアワーなど）の一覧を取得します
+// @Tags prices
+// @Produce json
+// @Success 200 {array} model.PriceRule
+// @Router /price-rules [get]
+func GetPriceRules(w http.ResponseWriter, r *http.Request) {
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	rules, err := loadPriceRules(r.Context(), conn, "")
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格ルールの取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// 価格ルール作成ハンドラー
+// @Summary 価格ルール作成
+// @Description 料理またはカテゴリに対する曜日・時間帯の価格ルールを登録します
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param body body PriceRuleRequest true "価格ルール"
+// @Success 201 {object} model.PriceRule
+// @Failure 400 {object} ErrorResponse
+// @Router /price-rules [post]
+func PostPriceRule(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodePriceRuleRequest(w, r)
+	if !ok {
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	if req.DishID != "" {
+		exists, err := dishExists(r.Context(), conn, req.DishID)
+		if err != nil {
+			writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+			return
+		}
+		if !exists {
+			writeErrorResponse(w, http.StatusBadRequest, "料理", "指定されたIDの料理が見つかりません")
+			return
+		}
+	}
+
+	var id string
+	err = conn.QueryRow(r.Context(),
+		`INSERT INTO price_rules (name, dish_id, category, days_of_week, start_time, end_time, starts_on, ends_on, adjustment_type, value, active)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+		priceRuleArgs(req)...,
+	).Scan(&id)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格ルールの登録に失敗しました")
+		return
+	}
+
+	writePriceRule(w, r, conn, id, http.StatusCreated)
+}
+
+// 価格ルール更新ハンドラー
+// @Summary 価格ルール更新
+// @Description 価格ルールを更新します
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param id path string true "価格ルールID"
+// @Param body body PriceRuleRequest true "価格ルール"
+// @Success 200 {object} model.PriceRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /price-rules/{id} [put]
+func PutPriceRule(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	req, ok := decodePriceRuleRequest(w, r)
+	if !ok {
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	if req.DishID != "" {
+		exists, err := dishExists(r.Context(), conn, req.DishID)
+		if err != nil {
+			writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+			return
+		}
+		if !exists {
+			writeErrorResponse(w, http.StatusBadRequest, "料理", "指定されたIDの料理が見つかりません")
+			return
+		}
+	}
+
+	result, err := conn.Exec(r.Context(),
+		`UPDATE price_rules
+		 SET name = $1, dish_id = $2, category = $3, days_of_week = $4, start_time = $5, end_time = $6,
+		     starts_on = $7, ends_on = $8, adjustment_type = $9, value = $10, active = $11
+		 WHERE id = $12`,
+		append(priceRuleArgs(req), id)...,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格ルールの更新に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "価格ルール", "指定されたIDの価格ルールが見つかりません")
+		return
+	}
+
+	writePriceRule(w, r, conn, id, http.StatusOK)
+}
+
+// 価格ルール削除ハンドラー
+// @Summary 価格ルール削除
+// @Description 価格ルールを削除します
+// @Tags prices
+// @Param id path string true "価格ルールID"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /price-rules/{id} [delete]
+func DeletePriceRule(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	result, err := conn.Exec(r.Context(), `DELETE FROM price_rules WHERE id = $1`, id)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格ルールの削除に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "価格ルール", "指定されたIDの価格ルールが見つかりません")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodePriceRuleRequest リクエストボディを読み取りバリデーションする
+// エラー時はレスポンスを書き込み false を返す
+func decodePriceRuleRequest(w http.ResponseWriter, r *http.Request) (PriceRuleRequest, bool) {
+	var req PriceRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return req, false
+	}
+	req.Category = strings.TrimSpace(req.Category)
+
+	if validationErrors := validatePriceRuleRequest(req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return req, false
+	}
+
+	return req, true
+}
+
+// priceRuleArgs 価格ルールの登録・更新に使うクエリ引数を組み立てる
+func priceRuleArgs(req PriceRuleRequest) []any {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	days := req.DaysOfWeek
+	if days == nil {
+		days = []int{}
+	}
+
+	return []any{
+		req.Name,
+		nullIfEmpty(req.DishID),
+		nullIfEmpty(req.Category),
+		days,
+		req.StartTime,
+		req.EndTime,
+		nullIfEmpty(req.StartsOn),
+		nullIfEmpty(req.EndsOn),
+		req.AdjustmentType,
+		req.Value,
+		active,
+	}
+}
+
+// writePriceRule 価格ルールを取得してレスポンスに書き込む
+func writePriceRule(w http.ResponseWriter, r *http.Request, q db.Querier, id string, statusCode int) {
+	rules, err := loadPriceRules(r.Context(), q, "WHERE id = $1", id)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格ルールの取得に失敗しました")
+		return
+	}
+	if len(rules) == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "価格ルール", "指定されたIDの価格ルールが見つかりません")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(rules[0])
+}
+
+// loadPriceRules 価格ルールを取得する（where には WHERE 句を指定）
+func loadPriceRules(ctx context.Context, q db.Querier, where string, args ...any) ([]model.PriceRule, error) {
+	rows, err := q.Query(ctx,
+		`SELECT id, name, COALESCE(dish_id::text, ''), COALESCE(category, ''), days_of_week,
+		        to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'),
+		        COALESCE(starts_on::text, ''), COALESCE(ends_on::text, ''),
+		        adjustment_type, value, active
+		 FROM price_rules `+where+` ORDER BY created_at`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PriceRule, error) {
+		var rule model.PriceRule
+		err := row.Scan(&rule.ID, &rule.Name, &rule.DishID, &rule.Category, &rule.DaysOfWeek,
+			&rule.StartTime, &rule.EndTime, &rule.StartsOn, &rule.EndsOn,
+			&rule.AdjustmentType, &rule.Value, &rule.Active)
+		return rule, err
+	})
+}
+
+// nullIfEmpty 空文字列を NULL として扱う
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
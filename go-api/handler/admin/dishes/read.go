@@ -1,12 +1,12 @@
 package admin
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
 	"github.com/smilemasa/go-api/db"
 	"github.com/smilemasa/go-api/model"
 	"github.com/smilemasa/go-api/utils"
@@ -17,26 +17,43 @@ import (
 // @Description 料理一覧を取得します
 // @Tags dishes
 // @Produce json
+// @Param at query string false "価格を判定する日時（RFC3339、省略時は現在日時）"
+// @Param lang query string false "表示ロケール（省略時は Accept-Language ヘッダーで判定）"
+// @Param Accept-Language header string false "表示ロケールの候補（ja, en, zh-Hans, zh-Hant, ko）"
 // @Success 200 {array} model.Dish
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /dishes [get]
 func AdminGetDishes(w http.ResponseWriter, r *http.Request) {
+	at, err := parsePriceAt(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "日時", "at はRFC3339形式で指定してください")
+		return
+	}
+
 	conn, err := db.ConnectDB()
 	if err != nil {
-		http.Error(w, "DB接続失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
 		return
 	}
-	defer conn.Close(context.Background())
+	defer conn.Release()
 
 	// GCSクライアントを取得
 	gcsClient, err := utils.GetGCSClient()
 	if err != nil {
-		http.Error(w, "GCSクライアント取得失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
 		return
 	}
 
-	rows, err := conn.Query(context.Background(), "SELECT id, name_ja, name_en, price, photo_url FROM dishes")
+	resolver, err := loadPriceResolver(r.Context(), conn, at)
 	if err != nil {
-		http.Error(w, "データ取得失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+
+	rows, err := conn.Query(r.Context(), "SELECT "+dishColumns+" FROM dishes WHERE deleted_at IS NULL")
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
 		return
 	}
 	defer rows.Close()
@@ -44,24 +61,33 @@ func AdminGetDishes(w http.ResponseWriter, r *http.Request) {
 	dishes := []model.Dish{}
 	for rows.Next() {
 		var d model.Dish
-		if err := rows.Scan(&d.ID, &d.NameJa, &d.NameEn, &d.Price, &d.Img); err != nil {
-			http.Error(w, "データスキャン失敗: "+err.Error(), http.StatusInternalServerError)
+		if err := scanDish(rows, &d); err != nil {
+			writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
 			return
 		}
-
-		if d.Img != "" {
-			// 1時間の有効期限で署名付きURLを生成
-			signedURL, err := gcsClient.CreateDownloadSignedURL(context.Background(), d.Img, 1*time.Hour)
-			if err != nil {
-				http.Error(w, "署名付きURL生成失敗: "+err.Error(), http.StatusInternalServerError)
-				return
-			}
-			d.Img = signedURL
-		}
+		applyEffectivePrice(resolver, &d, at)
 
 		dishes = append(dishes, d)
 	}
 
+	// 画像パスを署名付きURLに変換
+	imgs := make([]*string, len(dishes))
+	for i := range dishes {
+		imgs[i] = &dishes[i].Img
+	}
+	if err := signImageURLs(r.Context(), gcsClient, imgs); err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	// 表示ロケールの料理名を設定
+	locale := requestLocale(r)
+	if err := localizeDishes(r.Context(), conn, dishes, locale); err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の取得に失敗しました")
+		return
+	}
+	setLocaleHeaders(w, locale)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(dishes)
 }
@@ -71,70 +97,97 @@ func AdminGetDishes(w http.ResponseWriter, r *http.Request) {
 // @Description ID指定で料理の詳細を取得します
 // @Tags dishes
 // @Param id path string true "料理ID"
+// @Param at query string false "価格を判定する日時（RFC3339、省略時は現在日時）"
+// @Param lang query string false "表示ロケール（省略時は Accept-Language ヘッダーで判定）"
+// @Param Accept-Language header string false "表示ロケールの候補（ja, en, zh-Hans, zh-Hant, ko）"
 // @Produce json
 // @Success 200 {object} model.Dish
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 404 {object} model.ErrorResponse
-// @Failure 500 {object} model.ErrorResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /dishes/{id} [get]
 func AdminGetDish(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	dishID := vars["id"]
 
 	if dishID == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "料理IDが指定されていません"})
+		writeErrorResponse(w, http.StatusBadRequest, "ID", "料理IDが指定されていません")
+		return
+	}
+
+	at, err := parsePriceAt(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "日時", "at はRFC3339形式で指定してください")
 		return
 	}
 
 	conn, err := db.ConnectDB()
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "DB接続失敗: " + err.Error()})
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
 		return
 	}
-	defer conn.Close(context.Background())
+	defer conn.Release()
 
 	// GCSクライアントを取得
 	gcsClient, err := utils.GetGCSClient()
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "GCSクライアント取得失敗: " + err.Error()})
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
 		return
 	}
 
 	var dish model.Dish
-	err = conn.QueryRow(context.Background(), "SELECT id, name_ja, name_en, price, photo_url FROM dishes WHERE id = $1", dishID).
-		Scan(&dish.ID, &dish.NameJa, &dish.NameEn, &dish.Price, &dish.Img)
+	err = scanDish(conn.QueryRow(r.Context(), "SELECT "+dishColumns+" FROM dishes WHERE id = $1 AND deleted_at IS NULL", dishID), &dish)
 
 	if err != nil {
-		if err.Error() == "no rows in result set" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]string{"error": "指定されたIDの料理が見つかりません"})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "データ取得失敗: " + err.Error()})
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
 		return
 	}
 
+	// 指定日時の実売価格を適用
+	resolver, err := loadPriceResolver(r.Context(), conn, at)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+	applyEffectivePrice(resolver, &dish, at)
+
 	if dish.Img != "" {
-		// 1時間の有効期限で署名付きURLを生成
-		signedURL, err := gcsClient.CreateDownloadSignedURL(context.Background(), dish.Img, 1*time.Hour)
+		signedURL, err := signImageURL(r.Context(), gcsClient, dish.Img)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{"error": "署名付きURL生成失敗: " + err.Error()})
+			writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
 			return
 		}
 		dish.Img = signedURL
 	}
 
+	// オプショングループを取得
+	dish.ModifierGroups, err = loadModifierGroups(r.Context(), conn, dish.ID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "オプショングループの取得に失敗しました")
+		return
+	}
+
+	// 写真を取得
+	dish.Photos, err = loadDishPhotos(r.Context(), conn, gcsClient, dish.ID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "写真の取得に失敗しました")
+		return
+	}
+
+	// 表示ロケールの料理名を設定
+	locale := requestLocale(r)
+	localized := []model.Dish{dish}
+	if err := localizeDishes(r.Context(), conn, localized, locale); err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の取得に失敗しました")
+		return
+	}
+	dish = localized[0]
+	setLocaleHeaders(w, locale)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(dish)
 }
@@ -0,0 +1,245 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/auth"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// actorHeader 認証しない設定の場合に、操作したユーザーを識別するリクエストヘッダー
+const actorHeader = "X-User-Id"
+
+// 料理変更履歴一覧取得ハンドラー
+// @Summary 料理変更履歴一覧取得
+// @Description 料理の作成・更新・削除・復元の履歴を新しい順に取得します（削除済みの料理も取得可能）
+// @Tags revisions
+// @Produce json
+// @Param id path string true "料理ID"
+// @Success 200 {array} model.DishRevision
+// @Router /dishes/{id}/revisions [get]
+func GetDishRevisions(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(r.Context(),
+		`SELECT id, dish_id, action, old_values, new_values, actor, created_at
+		 FROM dish_revisions WHERE dish_id = $1 ORDER BY created_at DESC`,
+		dishID,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "変更履歴の取得に失敗しました")
+		return
+	}
+	revisions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.DishRevision, error) {
+		return scanDishRevision(row)
+	})
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "変更履歴の取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// 料理復元ハンドラー
+// @Summary 料理を履歴から復元
+// @Description 指定した履歴時点の値で料理を復元します（ゴミ箱の料理は元に戻し、完全削除済みの料理は再作成されます）
+// @Tags revisions
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param revisionId path string true "履歴ID"
+// @Success 200 {object} model.Dish
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/revisions/{revisionId}/restore [post]
+func RestoreDishRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dishID := vars["id"]
+	revisionID := vars["revisionId"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	revision, err := scanDishRevision(conn.QueryRow(r.Context(),
+		`SELECT id, dish_id, action, old_values, new_values, actor, created_at
+		 FROM dish_revisions WHERE id = $1 AND dish_id = $2`,
+		revisionID, dishID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "履歴", "指定されたIDの変更履歴が見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "変更履歴の取得に失敗しました")
+		return
+	}
+
+	// 履歴時点の値（削除履歴の場合は削除直前の値）
+	snapshot := revision.NewValues
+	if snapshot == nil {
+		snapshot = revision.OldValues
+	}
+	if snapshot == nil {
+		writeErrorResponse(w, http.StatusBadRequest, "履歴", "この履歴には復元できる値がありません")
+		return
+	}
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	var current model.Dish
+	err = scanDish(tx.QueryRow(r.Context(),
+		`SELECT `+dishColumns+` FROM dishes WHERE id = $1 FOR UPDATE`, dishID,
+	), &current)
+	var oldValues *model.DishSnapshot
+	switch {
+	case err == nil:
+		oldValues = dishSnapshot(current)
+		_, err = tx.Exec(r.Context(),
+			`UPDATE dishes SET name_ja = $1, name_en = $2, reading = $3, search_text = $4, price = $5, photo_url = $6, category = $7, allergens = $8, `+dishDetailAssignments(10)+`, deleted_at = NULL WHERE id = $9`,
+			append([]any{snapshot.NameJa, snapshot.NameEn, snapshot.Reading, dishSearchText(snapshot.NameJa, snapshot.NameEn, snapshot.Reading), snapshot.Price, snapshot.Img, snapshot.Category, snapshotAllergens(snapshot), dishID}, dishDetailArgs(snapshot.DishDetails)...)...,
+		)
+	case errors.Is(err, pgx.ErrNoRows):
+		// 完全削除済みの料理は同じIDで再作成する
+		_, err = tx.Exec(r.Context(),
+			`INSERT INTO dishes (id, name_ja, name_en, reading, search_text, price, photo_url, category, allergens, `+dishDetailColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, `+dishDetailPlaceholders(10)+`)`,
+			append([]any{dishID, snapshot.NameJa, snapshot.NameEn, snapshot.Reading, dishSearchText(snapshot.NameJa, snapshot.NameEn, snapshot.Reading), snapshot.Price, snapshot.Img, snapshot.Category, snapshotAllergens(snapshot)}, dishDetailArgs(snapshot.DishDetails)...)...,
+		)
+	}
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の復元に失敗しました")
+		return
+	}
+
+	if oldValues == nil || oldValues.Price != snapshot.Price {
+		if err := recordDirectPriceChange(r.Context(), tx, dishID, snapshot.Price); err != nil {
+			writeServerError(w, r, err, "データベース", "料理の復元に失敗しました")
+			return
+		}
+	}
+
+	if err := syncCoverPhoto(r.Context(), tx, dishID, snapshot.Img); err != nil {
+		writeServerError(w, r, err, "データベース", "料理の復元に失敗しました")
+		return
+	}
+
+	if err := recordDishRevision(r.Context(), tx, dishID, model.RevisionRestore, oldValues, snapshot, actorFromRequest(r)); err != nil {
+		writeServerError(w, r, err, "データベース", "変更履歴の記録に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "料理の復元に失敗しました")
+		return
+	}
+
+	var restored model.Dish
+	if err := scanDish(conn.QueryRow(r.Context(), `SELECT `+dishColumns+` FROM dishes WHERE id = $1`, dishID), &restored); err != nil {
+		writeServerError(w, r, err, "データベース", "復元データの取得に失敗しました")
+		return
+	}
+
+	now := time.Now()
+	resolver, err := loadPriceResolver(r.Context(), conn, now)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+	applyEffectivePrice(resolver, &restored, now)
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+	restored.Img, err = signImageURL(r.Context(), gcsClient, restored.Img)
+	if err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restored)
+}
+
+// recordDishRevision 料理の変更履歴を記録する
+func recordDishRevision(ctx context.Context, q db.Querier, dishID, action string, oldValues, newValues *model.DishSnapshot, actor string) error {
+	_, err := q.Exec(ctx,
+		`INSERT INTO dish_revisions (dish_id, action, old_values, new_values, actor) VALUES ($1, $2, $3, $4, $5)`,
+		dishID, action, oldValues, newValues, actor,
+	)
+	return err
+}
+
+// scanDishRevision 変更履歴の行を読み込む
+func scanDishRevision(row pgx.Row) (model.DishRevision, error) {
+	var rev model.DishRevision
+	err := row.Scan(&rev.ID, &rev.DishID, &rev.Action, &rev.OldValues, &rev.NewValues, &rev.Actor, &rev.CreatedAt)
+	return rev, err
+}
+
+// dishSnapshot 料理から履歴に記録する値を取り出す
+func dishSnapshot(d model.Dish) *model.DishSnapshot {
+	return &model.DishSnapshot{
+		NameJa:      d.NameJa,
+		NameEn:      d.NameEn,
+		Reading:     d.Reading,
+		Price:       d.Price,
+		Img:         d.Img,
+		Category:    d.Category,
+		Allergens:   d.Allergens,
+		DishDetails: d.DishDetails,
+	}
+}
+
+// snapshotAllergens 履歴のアレルゲンを取得する（アレルゲン追加前の履歴は空の配列）
+func snapshotAllergens(s *model.DishSnapshot) []string {
+	if s.Allergens == nil {
+		return []string{}
+	}
+	return s.Allergens
+}
+
+// recordDirectPriceChange 価格を直接変更した場合に、新しい価格を現在時刻から適用する価格変更として記録する
+// 適用済みの価格変更は料理の価格より優先されるため、記録しないと古い価格変更が残って新しい価格が反映されない
+// （適用済みの価格変更は価格の履歴として残す）
+func recordDirectPriceChange(ctx context.Context, q db.Querier, dishID string, price int) error {
+	_, err := q.Exec(ctx, `INSERT INTO price_changes (dish_id, price, effective_at) VALUES ($1, $2, now())`, dishID, price)
+	return err
+}
+
+// actorFromRequest リクエストから操作したユーザーを取得する
+// 認証している場合はトークンに対応する利用者IDを使い、リクエストヘッダーで詐称できないようにする
+func actorFromRequest(r *http.Request) string {
+	if actor, ok := auth.Actor(r.Context()); ok {
+		return actor
+	}
+	if actor := strings.TrimSpace(r.Header.Get(actorHeader)); actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
@@ -3,53 +3,175 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
-	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/smilemasa/go-api/db"
 	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/search"
 	"github.com/smilemasa/go-api/utils"
 )
 
+// didYouMeanHeader 検索結果がない場合に「もしかして」の検索条件（クエリ文字列形式）を返すレスポンスヘッダー
+const didYouMeanHeader = "X-Did-You-Mean"
+
+// searchTextColumn 正規化済みの検索対象文字列の列（pg_trgm の GIN インデックスあり）
+const searchTextColumn = "search_text"
+
+// searchNameJaColumn 日本語名の検索で対象にする列（日本語名とふりがな）を正規化した式
+// （<% は || と優先順位が同じため括弧で囲む）。インデックスがないため search_text で絞り込んだ行だけで評価する
+var searchNameJaColumn = "(" + search.SQLNormalize("name_ja") + " || ' ' || " + search.SQLNormalize("reading") + ")"
+
+// searchNameEnColumn 英語名の検索で対象にする列を正規化した式（searchNameJaColumn と同様に search_text で絞り込んでから評価する）
+var searchNameEnColumn = search.SQLNormalize("name_en")
+
 // 料理検索ハンドラー
 // @Summary 料理検索
-// @Description 日本語名・英語名で料理を部分一致検索します
+// @Description キーワード・日本語名・英語名・カテゴリ・価格帯・アレルゲンで料理を検索します。キーワードを指定した場合は関連度の高い順に返します。全角・半角、ひらがな・カタカナの違いを区別せず、ローマ字入力や英単語のタイプミスにも対応します。結果がない場合は X-Did-You-Mean ヘッダーで「もしかして」の検索条件を返します
 // @Tags dishes
 // @Produce json
-// @Param nameJa query string false "日本語名で部分一致検索"
-// @Param nameEn query string false "英語名で部分一致検索"
+// @Param q query string false "キーワード（日本語名・ふりがな・英語名が対象。空白区切りで複数指定するとすべてを含む料理を検索）"
+// @Param nameJa query string false "日本語名・ふりがなで検索"
+// @Param nameEn query string false "英語名で検索"
+// @Param category query string false "カテゴリ（完全一致）"
+// @Param minPrice query int false "最低価格（指定日時の実売価格）"
+// @Param maxPrice query int false "最高価格（指定日時の実売価格）"
+// @Param excludeAllergens query string false "含まない料理に絞り込むアレルゲン（カンマ区切り）"
+// @Param at query string false "価格を判定する日時（RFC3339、省略時は現在日時）"
+// @Param lang query string false "表示ロケール（省略時は Accept-Language ヘッダーで判定）"
 // @Success 200 {array} model.Dish
-// @Failure 400 {object} map[string]string
+// @Header 200 {string} X-Did-You-Mean "検索結果がない場合の「もしかして」の検索条件（例: q=curry）"
+// @Failure 400 {object} ErrorResponse
 // @Router /dishes/search [get]
 func SearchDishes(w http.ResponseWriter, r *http.Request) {
-	name := r.URL.Query().Get("name")
-	if name == "" {
-		http.Error(w, "name parameter is required", http.StatusBadRequest)
+	query := r.URL.Query()
+
+	req := SearchDishesRequest{
+		Q:        strings.TrimSpace(query.Get("q")),
+		NameJa:   strings.TrimSpace(query.Get("nameJa")),
+		NameEn:   strings.TrimSpace(query.Get("nameEn")),
+		Category: strings.TrimSpace(query.Get("category")),
+	}
+	// 以前の name パラメータは q として扱う
+	if req.Q == "" {
+		req.Q = strings.TrimSpace(query.Get("name"))
+	}
+	if v := query.Get("excludeAllergens"); v != "" {
+		req.ExcludeAllergens = parseAllergens(v)
+	}
+
+	var validationErrors []ValidationError
+	for _, p := range []struct {
+		name  string
+		field string
+		dst   **int
+	}{
+		{"minPrice", "最低価格", &req.MinPrice},
+		{"maxPrice", "最高価格", &req.MaxPrice},
+	} {
+		v := query.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: p.field, Message: "価格は整数で指定してください"})
+			continue
+		}
+		*p.dst = &n
+	}
+
+	at, err := parsePriceAt(r)
+	if err != nil {
+		validationErrors = append(validationErrors, ValidationError{Field: "日時", Message: "at はRFC3339形式で指定してください"})
+	}
+
+	if len(validationErrors) == 0 {
+		validationErrors = validateSearchDishesRequest(req)
+	}
+	if len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
 		return
 	}
 
 	conn, err := db.ConnectDB()
 	if err != nil {
-		http.Error(w, "DB接続失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
 		return
 	}
-	defer conn.Close(context.Background())
+	defer conn.Release()
+
+	// 検索条件を組み立てる
+	conds := []string{"deleted_at IS NULL"}
+	var scores []string
+	var args []any
+	textParams := []struct {
+		name   string
+		column string
+		value  string
+		fuzzy  bool
+	}{
+		{"q", searchTextColumn, req.Q, true},
+		{"nameJa", searchNameJaColumn, req.NameJa, false},
+		{"nameEn", searchNameEnColumn, req.NameEn, true},
+	}
+	for _, t := range textParams {
+		var corrections map[string][]string
+		if t.fuzzy {
+			corrections, err = typoCorrections(r.Context(), conn, t.value)
+			if err != nil {
+				writeServerError(w, r, err, "データベース", "料理の検索に失敗しました")
+				return
+			}
+		}
+		var match, score string
+		match, score, args = dishSearchCondition(t.column, t.value, corrections, args)
+		if match != "" {
+			conds = append(conds, match)
+			scores = append(scores, score)
+		}
+	}
+	if req.Category != "" {
+		args = append(args, req.Category)
+		conds = append(conds, fmt.Sprintf("category = $%d", len(args)))
+	}
+	if len(req.ExcludeAllergens) > 0 {
+		args = append(args, req.ExcludeAllergens)
+		conds = append(conds, fmt.Sprintf("NOT allergens && $%d::text[]", len(args)))
+	}
+
+	orderBy := "category, name_ja"
+	if len(scores) > 0 {
+		orderBy = strings.Join(scores, " + ") + " DESC, name_ja"
+	}
 
 	// GCSクライアントを取得
 	gcsClient, err := utils.GetGCSClient()
 	if err != nil {
-		http.Error(w, "GCSクライアント取得失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	resolver, err := loadPriceResolver(r.Context(), conn, at)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
 		return
 	}
 
 	rows, err := conn.Query(
-		context.Background(),
-		"SELECT id, name_ja, name_en, price, photo_url FROM dishes WHERE name_ja ILIKE $1 OR name_en ILIKE $1",
-		"%"+strings.ReplaceAll(name, " ", "%")+"%",
+		r.Context(),
+		"SELECT "+dishColumns+" FROM dishes WHERE "+strings.Join(conds, " AND ")+" ORDER BY "+orderBy,
+		args...,
 	)
 	if err != nil {
-		http.Error(w, "データ取得失敗: "+err.Error(), http.StatusInternalServerError)
+		writeServerError(w, r, err, "データベース", "料理の検索に失敗しました")
 		return
 	}
 	defer rows.Close()
@@ -57,39 +179,191 @@ func SearchDishes(w http.ResponseWriter, r *http.Request) {
 	dishes := []model.Dish{}
 	for rows.Next() {
 		var d model.Dish
-		if err := rows.Scan(&d.ID, &d.NameJa, &d.NameEn, &d.Price, &d.Img); err != nil {
-			http.Error(w, "データスキャン失敗: "+err.Error(), http.StatusInternalServerError)
+		if err := scanDish(rows, &d); err != nil {
+			writeServerError(w, r, err, "データベース", "料理の検索に失敗しました")
 			return
 		}
+		applyEffectivePrice(resolver, &d, at)
 
-		// 画像URLを署名付きURLに変換
-		if d.Img != "" {
-			var objectName string
-			if strings.HasPrefix(d.Img, "https://storage.googleapis.com/") {
-				// 完全URLからオブジェクト名を抽出
-				urlParts := strings.Split(d.Img, "/")
-				if len(urlParts) >= 2 {
-					objectName = urlParts[len(urlParts)-1] // ファイル名を取得
-				}
-			} else {
-				// ファイル名のみの場合はそのまま使用
-				objectName = d.Img
+		// 価格帯は指定日時の実売価格で判定する
+		if req.MinPrice != nil && d.Price < *req.MinPrice || req.MaxPrice != nil && d.Price > *req.MaxPrice {
+			continue
+		}
+
+		dishes = append(dishes, d)
+	}
+
+	// 画像URLを署名付きURLに変換
+	imgs := make([]*string, len(dishes))
+	for i := range dishes {
+		imgs[i] = &dishes[i].Img
+	}
+	if err := signImageURLs(r.Context(), gcsClient, imgs); err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	// 表示ロケールの料理名を設定
+	locale := requestLocale(r)
+	if err := localizeDishes(r.Context(), conn, dishes, locale); err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の取得に失敗しました")
+		return
+	}
+	setLocaleHeaders(w, locale)
+
+	// 検索結果がない場合は「もしかして」の検索条件を返す
+	if len(dishes) == 0 {
+		suggestion := url.Values{}
+		for _, t := range textParams {
+			if t.value == "" {
+				continue
 			}
+			corrected, err := didYouMean(r.Context(), conn, t.value)
+			if err != nil {
+				writeServerError(w, r, err, "データベース", "検索候補の取得に失敗しました")
+				return
+			}
+			if corrected != "" {
+				suggestion.Set(t.name, corrected)
+			}
+		}
+		if len(suggestion) > 0 {
+			w.Header().Set(didYouMeanHeader, suggestion.Encode())
+		}
+	}
 
-			if objectName != "" {
-				// 1時間の有効期限で署名付きURLを生成
-				signedURL, err := gcsClient.CreateDownloadSignedURL(context.Background(), objectName, 1*time.Hour)
-				if err != nil {
-					http.Error(w, "署名付きURL生成失敗: "+err.Error(), http.StatusInternalServerError)
-					return
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dishes)
+}
+
+// maxTypoCorrections 検索語の1語あたりにタイプミスとみなして検索に加える登録済みの語の上限
+const maxTypoCorrections = 5
+
+// typoCorrections 検索語の各語（Variants の候補ごと）について、登録済みの料理名の語のうち
+// 長さに応じた編集距離以内のものを返す。キーは候補の語。
+// 語はトライグラムの類似（pg_trgm の %、インデックスあり）で絞り込んでから編集距離で判定する。
+func typoCorrections(ctx context.Context, q db.Querier, query string) (map[string][]string, error) {
+	corrections := map[string][]string{}
+	for _, word := range strings.Fields(search.Normalize(query)) {
+		for _, v := range search.Variants(word) {
+			tolerance := search.EditTolerance(v)
+			if tolerance == 0 {
+				continue
+			}
+			rows, err := q.Query(ctx,
+				`SELECT w.word FROM dish_search_words w JOIN dishes d ON d.id = w.dish_id
+				 WHERE d.deleted_at IS NULL AND w.word % $1 AND w.word <> $1
+				   AND levenshtein_less_equal(w.word, $1, $2) <= $2
+				 GROUP BY w.word
+				 ORDER BY levenshtein(w.word, $1), w.word
+				 LIMIT $3`,
+				v, tolerance, maxTypoCorrections,
+			)
+			if err != nil {
+				return nil, err
+			}
+			words, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return nil, err
+			}
+			if len(words) > 0 {
+				corrections[v] = words
+			}
+		}
+	}
+	return corrections, nil
+}
+
+// didYouMean 検索語の各語を登録済みの料理名の語から最も近いものに置き換えた候補を返す
+// 置き換える語がない場合は空文字
+//
+// 候補の語はトライグラムの類似（pg_trgm の %、インデックスあり）で絞り込み、編集距離の近い順に選ぶ。
+func didYouMean(ctx context.Context, q db.Querier, query string) (string, error) {
+	words := strings.Fields(search.Normalize(query))
+	changed := false
+	for i, word := range words {
+		var candidate string
+		err := q.QueryRow(ctx,
+			`SELECT w.word FROM dish_search_words w JOIN dishes d ON d.id = w.dish_id
+			 WHERE d.deleted_at IS NULL AND w.word % $1
+			 GROUP BY w.word
+			 ORDER BY w.word = $1 DESC, levenshtein(w.word, $1), similarity(w.word, $1) DESC, w.word
+			 LIMIT 1`,
+			word,
+		).Scan(&candidate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if candidate != word {
+			words[i] = candidate
+			changed = true
+		}
+	}
+
+	if !changed {
+		return "", nil
+	}
+	return strings.Join(words, " "), nil
+}
+
+// dishSearchCondition 検索語から column（search_text など正規化済みの列）に対する条件と関連度の式を組み立てる
+//
+// 検索語は空白で区切った語ごとに正規化し、ローマ字の場合はひらがなに変換した語も候補にする。
+// すべての語が部分一致（LIKE）または類似（pg_trgm の <%）する料理を対象とし、
+// 部分一致を優先したうえで類似度の高い順に並べる。
+// corrections（typoCorrections の結果）に候補の語がある場合は、その語への部分一致も一致とみなす（タイプミス）。
+// column が search_text 以外の式の場合は、インデックスを使える search_text への同じ条件で先に絞り込む
+// （column の内容は search_text にも正規化して含まれるため、column で一致する料理は search_text でも一致する）。
+// args には既存のプレースホルダの値を渡し、追加した値を含めて返す。
+func dishSearchCondition(column, query string, corrections map[string][]string, args []any) (match, score string, _ []any) {
+	var conds, scores []string
+	for _, word := range strings.Fields(search.Normalize(query)) {
+		var wordConds, indexConds, wordScores []string
+		for _, v := range search.Variants(word) {
+			args = append(args, "%"+escapeLike(v)+"%", v)
+			like := fmt.Sprintf("$%d", len(args)-1)
+			term := fmt.Sprintf("$%d", len(args))
+
+			wordConds = append(wordConds,
+				column+" LIKE "+like,
+				term+" <% "+column,
+			)
+			if column != searchTextColumn {
+				indexConds = append(indexConds,
+					searchTextColumn+" LIKE "+like,
+					term+" <% "+searchTextColumn,
+				)
+			}
+			for _, corrected := range corrections[v] {
+				args = append(args, "%"+escapeLike(corrected)+"%")
+				correctedLike := fmt.Sprintf("$%d", len(args))
+				wordConds = append(wordConds, column+" LIKE "+correctedLike)
+				if column != searchTextColumn {
+					indexConds = append(indexConds, searchTextColumn+" LIKE "+correctedLike)
 				}
-				d.Img = signedURL
 			}
+			wordScores = append(wordScores, fmt.Sprintf(
+				"(CASE WHEN %[1]s LIKE %[2]s THEN 1 ELSE 0 END + word_similarity(%[3]s, %[1]s))",
+				column, like, term,
+			))
 		}
+		if len(indexConds) > 0 {
+			conds = append(conds, "("+strings.Join(indexConds, " OR ")+")")
+		}
+		conds = append(conds, "("+strings.Join(wordConds, " OR ")+")")
+		scores = append(scores, "GREATEST("+strings.Join(wordScores, ", ")+")")
+	}
 
-		dishes = append(dishes, d)
+	if len(conds) == 0 {
+		return "", "", args
 	}
+	return "(" + strings.Join(conds, " AND ") + ")", "(" + strings.Join(scores, " + ") + ")", args
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(dishes)
+// escapeLike LIKE のワイルドカード文字をエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
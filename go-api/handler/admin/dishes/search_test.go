@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDishSearchConditionNarrowsBySearchText(t *testing.T) {
+	tests := []struct {
+		name          string
+		column        string
+		wantPrefilter bool
+	}{
+		{name: "search_text", column: searchTextColumn, wantPrefilter: false},
+		{name: "nameJa", column: searchNameJaColumn, wantPrefilter: true},
+		{name: "nameEn", column: searchNameEnColumn, wantPrefilter: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, _, args := dishSearchCondition(tt.column, "カレー", nil, nil)
+			// search_text 以外の式は、インデックスを使える search_text への条件で先に絞り込む
+			prefilter := "((search_text LIKE $1 OR $2 <% search_text) AND ("
+			if got := strings.HasPrefix(match, prefilter); got != tt.wantPrefilter {
+				t.Errorf("match = %s, starts with %q = %v, want %v", match, prefilter, got, tt.wantPrefilter)
+			}
+			if !strings.Contains(match, tt.column+" LIKE $1") {
+				t.Errorf("match = %s, want condition on %s", match, tt.column)
+			}
+			if len(args) != 2 || args[0] != "%かれー%" || args[1] != "かれー" {
+				t.Errorf("args = %v", args)
+			}
+		})
+	}
+}
+
+func TestDishSearchConditionTypoCorrections(t *testing.T) {
+	corrections := map[string][]string{"chiken": {"chicken", "chikuwa"}}
+	match, _, args := dishSearchCondition(searchNameEnColumn, "Chiken", corrections, nil)
+
+	wantArgs := []any{"%chiken%", "chiken", "%chicken%", "%chikuwa%", "%ちけん%", "ちけん"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args = %v, want %v", args, wantArgs)
+	}
+	// 補正した語への部分一致は search_text（インデックスあり）と英語名の両方の条件に加える
+	for _, want := range []string{
+		"search_text LIKE $3", "search_text LIKE $4",
+		searchNameEnColumn + " LIKE $3", searchNameEnColumn + " LIKE $4",
+	} {
+		if !strings.Contains(match, want) {
+			t.Errorf("match = %s, want containing %q", match, want)
+		}
+	}
+	// 行ごとに語を分割して編集距離を計算する条件は使わない
+	if strings.Contains(match, "levenshtein") || strings.Contains(match, "regexp_split_to_table") {
+		t.Errorf("match = %s, want no per-row edit distance", match)
+	}
+}
@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/search"
+)
+
+const (
+	// defaultSuggestLimit 入力補完の候補数（省略時）
+	defaultSuggestLimit = 10
+	// maxSuggestLimit 入力補完の候補数の上限
+	maxSuggestLimit = 20
+)
+
+// DishSuggestion 入力補完の候補
+type DishSuggestion struct {
+	ID     string `json:"id"`     // 料理ID
+	NameJa string `json:"nameJa"` // 日本語名
+	NameEn string `json:"nameEn"` // 英語名
+}
+
+// 料理名入力補完ハンドラー
+// @Summary 料理名の入力補完
+// @Description 入力途中の文字列で始まる日本語名・ふりがな・英単語を持つ料理を候補として返します。ひらがな・カタカナ、ローマ字入力の違いは区別しません
+// @Tags dishes
+// @Produce json
+// @Param q query string true "入力途中の文字列"
+// @Param limit query int false "候補数（1〜20、省略時は10）"
+// @Success 200 {array} DishSuggestion
+// @Failure 400 {object} ErrorResponse
+// @Router /dishes/suggest [get]
+func SuggestDishes(w http.ResponseWriter, r *http.Request) {
+	query := search.Normalize(r.URL.Query().Get("q"))
+	if query == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "キーワード", "この項目は必須です")
+		return
+	}
+	if len([]rune(query)) > 100 {
+		writeErrorResponse(w, http.StatusBadRequest, "キーワード", "最大100文字以下で入力してください")
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSuggestLimit {
+			writeErrorResponse(w, http.StatusBadRequest, "候補数", fmt.Sprintf("候補数は1〜%dで指定してください", maxSuggestLimit))
+			return
+		}
+		limit = n
+	}
+
+	// 入力全体を前方一致させる（語の先頭で始まるものも対象）
+	var conds, starts []string
+	var args []any
+	for _, v := range search.Variants(query) {
+		args = append(args, escapeLike(v)+"%", "% "+escapeLike(v)+"%")
+		prefix := fmt.Sprintf("$%d", len(args)-1)
+		wordPrefix := fmt.Sprintf("$%d", len(args))
+		conds = append(conds, "search_text LIKE "+prefix, "search_text LIKE "+wordPrefix)
+		starts = append(starts, "search_text LIKE "+prefix)
+	}
+	args = append(args, limit)
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	// 日本語名の先頭で一致するものを優先し、短い名前から並べる
+	rows, err := conn.Query(r.Context(),
+		`SELECT id, name_ja, name_en FROM dishes
+		 WHERE deleted_at IS NULL AND (`+strings.Join(conds, " OR ")+`)
+		 ORDER BY (`+strings.Join(starts, " OR ")+`) DESC, length(name_ja), name_ja
+		 LIMIT $`+strconv.Itoa(len(args)),
+		args...,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "入力候補の取得に失敗しました")
+		return
+	}
+	defer rows.Close()
+
+	suggestions := []DishSuggestion{}
+	for rows.Next() {
+		var s DishSuggestion
+		if err := rows.Scan(&s.ID, &s.NameJa, &s.NameEn); err != nil {
+			writeServerError(w, r, err, "データベース", "入力候補の取得に失敗しました")
+			return
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestions)
+}
@@ -0,0 +1,314 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/i18n"
+	"github.com/smilemasa/go-api/model"
+)
+
+// 料理翻訳一覧取得ハンドラー
+// @Summary 料理翻訳一覧取得
+// @Description 料理の日本語・英語以外の翻訳をロケール順に取得します
+// @Tags translations
+// @Produce json
+// @Param id path string true "料理ID"
+// @Success 200 {array} model.DishTranslation
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/translations [get]
+func GetDishTranslations(w http.ResponseWriter, r *http.Request) {
+	dishID := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	translations, err := loadDishTranslations(r.Context(), conn, []string{dishID})
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translations)
+}
+
+// 料理翻訳登録・更新ハンドラー
+// @Summary 料理翻訳登録・更新
+// @Description 指定したロケールの料理名・説明を登録します（登録済みの場合は上書き）。日本語・英語は料理の更新で変更してください
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "料理ID"
+// @Param locale path string true "ロケール（zh-Hans, zh-Hant, ko）"
+// @Param body body TranslationRequest true "翻訳"
+// @Success 200 {object} model.DishTranslation
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/translations/{locale} [put]
+func PutDishTranslation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dishID := vars["id"]
+
+	locale, ok := translationLocale(w, vars["locale"])
+	if !ok {
+		return
+	}
+
+	var req TranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return
+	}
+
+	if validationErrors := validateTranslationRequest(req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	exists, err := dishExists(r.Context(), conn, dishID)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	t := model.DishTranslation{DishID: dishID, Locale: locale, Name: req.Name, Description: req.Description}
+	err = conn.QueryRow(r.Context(),
+		`INSERT INTO dish_translations (dish_id, locale, name, description) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (dish_id, locale) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = now()
+		 RETURNING updated_at`,
+		dishID, locale, req.Name, req.Description,
+	).Scan(&t.UpdatedAt)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の登録に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t)
+}
+
+// 料理翻訳削除ハンドラー
+// @Summary 料理翻訳削除
+// @Description 指定したロケールの翻訳を削除します
+// @Tags translations
+// @Param id path string true "料理ID"
+// @Param locale path string true "ロケール（zh-Hans, zh-Hant, ko）"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/translations/{locale} [delete]
+func DeleteDishTranslation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	locale, ok := translationLocale(w, vars["locale"])
+	if !ok {
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	result, err := conn.Exec(r.Context(),
+		`DELETE FROM dish_translations WHERE dish_id = $1 AND locale = $2`,
+		vars["id"], locale,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳の削除に失敗しました")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "翻訳", "指定されたロケールの翻訳が見つかりません")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 翻訳不足一覧取得ハンドラー
+// @Summary 翻訳不足一覧取得
+// @Description 翻訳が登録されていないロケールがある料理を、不足しているロケールとともに取得します
+// @Tags translations
+// @Produce json
+// @Param locale query string false "対象のロケール（省略時は日本語・英語以外のすべて）"
+// @Success 200 {array} model.MissingTranslations
+// @Failure 400 {object} ErrorResponse
+// @Router /translations/missing [get]
+func GetMissingTranslations(w http.ResponseWriter, r *http.Request) {
+	var locales []string
+	if v := r.URL.Query().Get("locale"); v != "" {
+		locale, ok := translationLocale(w, v)
+		if !ok {
+			return
+		}
+		locales = []string{locale}
+	} else {
+		for _, l := range i18n.Supported {
+			if !i18n.IsBuiltin(l) {
+				locales = append(locales, l)
+			}
+		}
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(r.Context(),
+		`SELECT d.id, d.name_ja, d.name_en, array_agg(l.locale ORDER BY l.ord)
+		 FROM dishes d
+		 CROSS JOIN unnest($1::text[]) WITH ORDINALITY AS l (locale, ord)
+		 WHERE d.deleted_at IS NULL
+		   AND NOT EXISTS (SELECT 1 FROM dish_translations t WHERE t.dish_id = d.id AND t.locale = l.locale)
+		 GROUP BY d.id, d.name_ja, d.name_en
+		 ORDER BY d.name_ja`,
+		locales,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳不足の取得に失敗しました")
+		return
+	}
+	missing, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.MissingTranslations, error) {
+		var m model.MissingTranslations
+		err := row.Scan(&m.DishID, &m.NameJa, &m.NameEn, &m.MissingLocales)
+		return m, err
+	})
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "翻訳不足の取得に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(missing)
+}
+
+// translationLocale 翻訳を登録できるロケールかを確認し、表記を揃えて返す
+// 不正な場合はエラーレスポンスを書き込んで false を返す
+func translationLocale(w http.ResponseWriter, value string) (string, bool) {
+	locale, ok := i18n.Canonical(value)
+	if !ok {
+		writeErrorResponse(w, http.StatusBadRequest, "ロケール", "対応していないロケールです")
+		return "", false
+	}
+	if i18n.IsBuiltin(locale) {
+		writeErrorResponse(w, http.StatusBadRequest, "ロケール", "日本語・英語の料理名は料理の更新で変更してください")
+		return "", false
+	}
+	return locale, true
+}
+
+// loadDishTranslations 料理の翻訳を取得する
+func loadDishTranslations(ctx context.Context, q db.Querier, dishIDs []string) ([]model.DishTranslation, error) {
+	rows, err := q.Query(ctx,
+		`SELECT dish_id, locale, name, description, updated_at FROM dish_translations
+		 WHERE dish_id = ANY($1::uuid[]) ORDER BY dish_id, locale`,
+		dishIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.DishTranslation, error) {
+		var t model.DishTranslation
+		err := row.Scan(&t.DishID, &t.Locale, &t.Name, &t.Description, &t.UpdatedAt)
+		return t, err
+	})
+}
+
+// requestLocale 表示ロケールを決める（lang クエリパラメータ、なければ Accept-Language ヘッダー）
+func requestLocale(r *http.Request) string {
+	if locale, ok := i18n.Canonical(r.URL.Query().Get("lang")); ok {
+		return locale
+	}
+	return i18n.Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// setLocaleHeaders 表示ロケールに関するレスポンスヘッダーを設定する
+func setLocaleHeaders(w http.ResponseWriter, locale string) {
+	w.Header().Set("Content-Language", locale)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+// localizeDishes 料理の表示名・説明を表示ロケールで設定する
+// 訳がない場合は代替ロケール（zh-Hant → zh-Hans → en → ja など）の値を使う
+func localizeDishes(ctx context.Context, q db.Querier, dishes []model.Dish, locale string) error {
+	if len(dishes) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(dishes))
+	for i, d := range dishes {
+		ids[i] = d.ID
+	}
+	translations, err := loadDishTranslations(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+
+	byDish := map[string]map[string]model.DishTranslation{}
+	for _, t := range translations {
+		if byDish[t.DishID] == nil {
+			byDish[t.DishID] = map[string]model.DishTranslation{}
+		}
+		byDish[t.DishID][t.Locale] = t
+	}
+
+	for i := range dishes {
+		d := &dishes[i]
+	chain:
+		for _, l := range i18n.FallbackChain(locale) {
+			switch l {
+			case i18n.Japanese:
+				d.Locale, d.Name, d.Description = l, d.NameJa, d.DescriptionJa
+			case i18n.English:
+				d.Locale, d.Name, d.Description = l, d.NameEn, d.DescriptionEn
+			default:
+				t, ok := byDish[d.ID][l]
+				if !ok {
+					continue
+				}
+				d.Locale, d.Name, d.Description = l, t.Name, t.Description
+			}
+			break chain
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// ゴミ箱の料理一覧取得ハンドラー
+// @Summary ゴミ箱の料理一覧取得
+// @Description 削除された料理を削除日時の新しい順に取得します（価格は他の料理と同じく現在の実売価格）
+// @Tags dishes
+// @Produce json
+// @Success 200 {array} model.Dish
+// @Router /dishes/trash [get]
+func AdminGetDeletedDishes(w http.ResponseWriter, r *http.Request) {
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	now := time.Now()
+	resolver, err := loadPriceResolver(r.Context(), conn, now)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+
+	rows, err := conn.Query(r.Context(),
+		"SELECT "+dishColumns+", deleted_at FROM dishes WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "ゴミ箱の料理の取得に失敗しました")
+		return
+	}
+	defer rows.Close()
+
+	dishes := []model.Dish{}
+	for rows.Next() {
+		var d model.Dish
+		if err := rows.Scan(append(dishFields(&d), &d.DeletedAt)...); err != nil {
+			writeServerError(w, r, err, "データベース", "ゴミ箱の料理の取得に失敗しました")
+			return
+		}
+		applyEffectivePrice(resolver, &d, now)
+
+		dishes = append(dishes, d)
+	}
+
+	imgs := make([]*string, len(dishes))
+	for i := range dishes {
+		imgs[i] = &dishes[i].Img
+	}
+	if err := signImageURLs(r.Context(), gcsClient, imgs); err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dishes)
+}
+
+// ゴミ箱からの料理復元ハンドラー
+// @Summary ゴミ箱から料理を復元
+// @Description 削除された料理をゴミ箱から元に戻します
+// @Tags dishes
+// @Produce json
+// @Param id path string true "料理ID"
+// @Success 200 {object} model.Dish
+// @Failure 404 {object} ErrorResponse
+// @Router /dishes/{id}/restore [post]
+func RestoreDish(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	var restored model.Dish
+	err = scanDish(tx.QueryRow(r.Context(),
+		"UPDATE dishes SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING "+dishColumns,
+		id,
+	), &restored)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeErrorResponse(w, http.StatusNotFound, "料理", "ゴミ箱に指定されたIDの料理が見つかりません")
+			return
+		}
+		writeServerError(w, r, err, "データベース", "料理の復元に失敗しました")
+		return
+	}
+
+	// 変更履歴を記録
+	if err := recordDishRevision(r.Context(), tx, id, model.RevisionRestore, nil, dishSnapshot(restored), actorFromRequest(r)); err != nil {
+		writeServerError(w, r, err, "データベース", "変更履歴の記録に失敗しました")
+		return
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "料理の復元に失敗しました")
+		return
+	}
+
+	now := time.Now()
+	resolver, err := loadPriceResolver(r.Context(), conn, now)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+	applyEffectivePrice(resolver, &restored, now)
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+	restored.Img, err = signImageURL(r.Context(), gcsClient, restored.Img)
+	if err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restored)
+}
@@ -3,14 +3,17 @@ package admin
 import (
 	"context"
 	"encoding/json"
-	"io"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
 	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/metrics"
 	"github.com/smilemasa/go-api/model"
 	"github.com/smilemasa/go-api/utils"
 )
@@ -22,10 +25,23 @@ import (
 // @Accept multipart/form-data
 // @Produce json
 // @Param id path string true "料理ID"
-// @Param photo formData file false "料理の写真ファイル（変更する場合のみ）"
+// @Param photo formData file false "料理の写真ファイル（代表写真を変更する場合のみ、以前の代表写真はギャラリーに残る）"
+// @Param photoKey formData string false "直接アップロードした写真のキー（photo の代わりに指定する）"
 // @Param nameJa formData string false "料理名（日本語）"
 // @Param nameEn formData string false "料理名（英語）"
+// @Param reading formData string false "ふりがな（空文字で解除）"
 // @Param price formData int false "料理の価格"
+// @Param category formData string false "カテゴリ（空文字で解除）"
+// @Param allergens formData string false "アレルゲン（カンマ区切り、空文字で解除）"
+// @Param descriptionJa formData string false "説明（日本語、空文字で解除）"
+// @Param descriptionEn formData string false "説明（英語、空文字で解除）"
+// @Param ingredients formData string false "原材料（カンマ・読点・改行区切り、空文字で解除）"
+// @Param calories formData integer false "エネルギー（kcal、空文字で解除）"
+// @Param protein formData number false "たんぱく質（g、空文字で解除）"
+// @Param fat formData number false "脂質（g、空文字で解除）"
+// @Param carbohydrate formData number false "炭水化物（g、空文字で解除）"
+// @Param spiceLevel formData integer false "辛さ（0〜5）"
+// @Param portionSize formData string false "量（空文字で解除）"
 // @Success 200 {object} model.Dish
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
@@ -43,6 +59,13 @@ func PutDish(w http.ResponseWriter, r *http.Request) {
 	nameJa := r.FormValue("nameJa")
 	nameEn := r.FormValue("nameEn")
 	priceStr := r.FormValue("price")
+	_, hasReading := r.Form["reading"]
+	reading := strings.TrimSpace(r.FormValue("reading"))
+	_, hasCategory := r.Form["category"]
+	category := strings.TrimSpace(r.FormValue("category"))
+	_, hasAllergens := r.Form["allergens"]
+	allergens := parseAllergens(r.FormValue("allergens"))
+	details, detailErrors := parseDishDetails(formField(r.Form))
 
 	// バリデーション用のリクエスト構造体を作成
 	var price int
@@ -56,13 +79,18 @@ func PutDish(w http.ResponseWriter, r *http.Request) {
 	}
 
 	updateRequest := UpdateDishRequest{
-		NameJa: nameJa,
-		NameEn: nameEn,
-		Price:  price,
+		NameJa:    nameJa,
+		NameEn:    nameEn,
+		Reading:   reading,
+		Price:     price,
+		Category:  category,
+		Allergens: allergens,
+
+		DishDetailsRequest: details,
 	}
 
 	// バリデーション実行
-	if validationErrors := validateUpdateDishRequest(updateRequest); len(validationErrors) > 0 {
+	if validationErrors := append(detailErrors, validateUpdateDishRequest(updateRequest)...); len(validationErrors) > 0 {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
@@ -78,21 +106,56 @@ func PutDish(w http.ResponseWriter, r *http.Request) {
 
 	conn, err := db.ConnectDB()
 	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "データベース", "データベース接続に失敗しました")
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	// 写真をアップロードする前に料理の存在を確認する（更新する値は同時の更新で失われないようトランザクション内で取得する）
+	exists, err := dishExists(r.Context(), conn, id)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
+	if !exists {
+		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+		return
+	}
+
+	// GCSクライアントを取得
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
 		return
 	}
-	defer conn.Close(context.Background())
 
-	// 現在の料理情報を取得
+	// 写真の処理（オプショナル。photoKey で直接アップロード済みの写真を指定するか、photo でファイルを送信する）
+	photo, ok := receivePhoto(r.Context(), w, r, conn, gcsClient, false)
+	if !ok {
+		return
+	}
+
+	tx, err := conn.Begin(r.Context())
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "トランザクションの開始に失敗しました")
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(r.Context()))
+
+	// 現在の料理情報を行をロックして取得する（同時に更新された項目を古い値で上書きしないようにする）
 	var currentDish model.Dish
-	row := conn.QueryRow(context.Background(),
-		`SELECT id, name_ja, name_en, price, photo_url FROM dishes WHERE id = $1`,
+	err = scanDish(tx.QueryRow(r.Context(),
+		`SELECT `+dishColumns+` FROM dishes WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
 		id,
-	)
-	if err := row.Scan(&currentDish.ID, &currentDish.NameJa, &currentDish.NameEn, &currentDish.Price, &currentDish.Img); err != nil {
+	), &currentDish)
+	if errors.Is(err, pgx.ErrNoRows) {
 		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
 		return
 	}
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "料理の取得に失敗しました")
+		return
+	}
 
 	// 現在の値で初期化
 	updateDish := currentDish
@@ -107,106 +170,105 @@ func PutDish(w http.ResponseWriter, r *http.Request) {
 	if priceStr != "" {
 		updateDish.Price = price // 既にバリデーション済み
 	}
+	if hasReading {
+		updateDish.Reading = reading
+	}
+	if hasCategory {
+		// カテゴリは空文字での解除を許可
+		updateDish.Category = category
+	}
+	if hasAllergens {
+		updateDish.Allergens = allergens
+	}
+	applyDishDetails(&updateDish.DishDetails, details, formField(r.Form))
 
-	// GCSクライアントを取得
-	gcsClient, err := utils.GetGCSClient()
+	if photo.objectName != "" {
+		// 画像URLを更新
+		updateDish.Img = photo.objectName
+	}
+
+	// 料理情報を更新
+	_, err = tx.Exec(r.Context(),
+		`UPDATE dishes SET name_ja = $1, name_en = $2, reading = $3, search_text = $4, price = $5, photo_url = $6, category = $7, allergens = $8, `+dishDetailAssignments(10)+` WHERE id = $9 AND deleted_at IS NULL`,
+		append([]any{updateDish.NameJa, updateDish.NameEn, updateDish.Reading, dishSearchText(updateDish.NameJa, updateDish.NameEn, updateDish.Reading), updateDish.Price, updateDish.Img, updateDish.Category, updateDish.Allergens, id}, dishDetailArgs(updateDish.DishDetails)...)...,
+	)
 	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		writeServerError(w, r, err, "データベース", "更新に失敗しました")
 		return
 	}
 
-	// 写真ファイルの処理（オプショナル）
-	file, handler, err := r.FormFile("photo")
-	if err == nil {
-		defer file.Close()
-
-		// ファイル拡張子のチェック
-		if !isValidImageFormat(handler.Filename) {
-			writeErrorResponse(w, http.StatusBadRequest, "写真", "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています")
+	// 価格を直接変更した場合、適用済みの価格変更より新しい価格変更として記録する
+	if priceStr != "" {
+		if err := recordDirectPriceChange(r.Context(), tx, id, updateDish.Price); err != nil {
+			writeServerError(w, r, err, "データベース", "更新に失敗しました")
 			return
 		}
+	}
 
-		// ファイル名の生成
-		fileName := generateSecureFileName(handler.Filename)
-
-		// ファイル内容を読み取り
-		fileBytes, err := io.ReadAll(file)
-		if err != nil {
-			writeErrorResponse(w, http.StatusInternalServerError, "写真", "ファイルの読み取りに失敗しました")
+	// 直接アップロードした写真のアップロード枠を使用済みにする
+	if photo.fromSlot() {
+		if err := consumeUpload(r.Context(), tx, photo.photoKey); err != nil {
+			writeUploadError(w, r, err)
 			return
 		}
+	}
 
-		// Content-Typeの設定
-		ext := getFileExtension(handler.Filename)
-		contentType := "image/jpeg"
-		switch ext {
-		case ".png":
-			contentType = "image/png"
-		case ".webp":
-			contentType = "image/webp"
-		}
-
-		// GCSにアップロード
-		err = gcsClient.UploadFile(context.Background(), fileName, fileBytes, contentType)
-		if err != nil {
-			writeErrorResponse(w, http.StatusInternalServerError, "写真", "写真のアップロードに失敗しました")
+	// 写真を変更した場合は新しい写真を代表写真にする（以前の代表写真はギャラリーに残す）
+	if updateDish.Img != currentDish.Img {
+		if err := syncCoverPhoto(r.Context(), tx, id, updateDish.Img); err != nil {
+			writeServerError(w, r, err, "データベース", "写真の登録に失敗しました")
 			return
 		}
-
-		// 画像URLを更新
-		updateDish.Img = fileName
 	}
 
-	// 料理情報を更新
-	result, err := conn.Exec(context.Background(),
-		`UPDATE dishes SET name_ja = $1, name_en = $2, price = $3, photo_url = $4 WHERE id = $5`,
-		updateDish.NameJa, updateDish.NameEn, updateDish.Price, updateDish.Img, id,
-	)
-	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "データベース", "更新に失敗しました")
+	// 変更履歴を記録
+	if err := recordDishRevision(r.Context(), tx, id, model.RevisionUpdate, dishSnapshot(currentDish), dishSnapshot(updateDish), actorFromRequest(r)); err != nil {
+		writeServerError(w, r, err, "データベース", "変更履歴の記録に失敗しました")
 		return
 	}
 
-	if result.RowsAffected() == 0 {
-		writeErrorResponse(w, http.StatusNotFound, "料理", "指定されたIDの料理が見つかりません")
+	// 料理名が変わった場合は他の言語の料理名の下書きを作り直す（失敗しても更新は取り消さない）
+	if updateDish.NameJa != currentDish.NameJa || updateDish.NameEn != currentDish.NameEn {
+		if err := suggestTranslations(r.Context(), tx, updateDish); err != nil {
+			slog.ErrorContext(r.Context(), "翻訳の下書きの作成に失敗しました", "dish_id", id, "error", err)
+		}
+	}
+
+	if err := tx.Commit(r.Context()); err != nil {
+		writeServerError(w, r, err, "データベース", "更新に失敗しました")
 		return
 	}
+	if photo.objectName != "" {
+		releaseUpload(r.Context(), gcsClient, photo)
+		metrics.PhotosUploaded.WithLabelValues(uploadMethod(photo.fromSlot())).Inc()
+	}
 
 	// 更新された料理情報を取得して返す
 	var updatedDish model.Dish
-	row = conn.QueryRow(context.Background(),
-		`SELECT id, name_ja, name_en, price, photo_url FROM dishes WHERE id = $1`,
+	row := conn.QueryRow(r.Context(),
+		`SELECT `+dishColumns+` FROM dishes WHERE id = $1`,
 		id,
 	)
 
-	if err := row.Scan(&updatedDish.ID, &updatedDish.NameJa, &updatedDish.NameEn, &updatedDish.Price, &updatedDish.Img); err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "データベース", "更新データの取得に失敗しました")
+	if err := scanDish(row, &updatedDish); err != nil {
+		writeServerError(w, r, err, "データベース", "更新データの取得に失敗しました")
 		return
 	}
 
-	// 画像URLを署名付きURLに変換
-	if updatedDish.Img != "" {
-		var objectName string
-		if strings.HasPrefix(updatedDish.Img, "https://storage.googleapis.com/") {
-			// 完全URLからオブジェクト名を抽出
-			urlParts := strings.Split(updatedDish.Img, "/")
-			if len(urlParts) >= 2 {
-				objectName = urlParts[len(urlParts)-1] // ファイル名を取得
-			}
-		} else {
-			// ファイル名のみの場合はそのまま使用
-			objectName = updatedDish.Img
-		}
+	// 現在の実売価格を適用
+	now := time.Now()
+	resolver, err := loadPriceResolver(r.Context(), conn, now)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "価格情報の取得に失敗しました")
+		return
+	}
+	applyEffectivePrice(resolver, &updatedDish, now)
 
-		if objectName != "" {
-			// 1時間の有効期限で署名付きURLを生成
-			signedURL, err := gcsClient.CreateDownloadSignedURL(context.Background(), objectName, 1*time.Hour)
-			if err != nil {
-				writeErrorResponse(w, http.StatusInternalServerError, "画像", "署名付きURL生成に失敗しました")
-				return
-			}
-			updatedDish.Img = signedURL
-		}
+	// 画像URLを署名付きURL（ハッシュ名の写真は公開URL）に変換
+	updatedDish.Img, err = signImageURL(r.Context(), gcsClient, updatedDish.Img)
+	if err != nil {
+		writeServerError(w, r, err, "画像", "署名付きURL生成に失敗しました")
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,261 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/utils"
+)
+
+const (
+	// maxPhotoSize 写真1枚あたりの最大サイズ
+	maxPhotoSize = 10 << 20
+	// uploadURLExpiration アップロード用の署名付きURLの有効期限
+	uploadURLExpiration = 15 * time.Minute
+	// uploadSlotLifetime アップロード枠の有効期限（この間に料理の作成・更新で使う）
+	uploadSlotLifetime = 1 * time.Hour
+)
+
+// UploadSlot 写真のアップロード枠
+type UploadSlot struct {
+	PhotoKey  string            `json:"photoKey"`  // 料理の作成・更新時に指定するキー（オブジェクト名）
+	UploadURL string            `json:"uploadUrl"` // アップロード用の署名付きURL
+	Method    string            `json:"method"`    // アップロード時のHTTPメソッド
+	Headers   map[string]string `json:"headers"`   // アップロード時に必ず送るヘッダー
+	ExpiresAt time.Time         `json:"expiresAt"` // アップロード枠の有効期限
+}
+
+// uploadError 写真の確認エラー（利用者に返すメッセージを持つ）
+type uploadError struct {
+	message string
+}
+
+func (e *uploadError) Error() string {
+	return e.message
+}
+
+// 写真アップロード枠発行ハンドラー
+// @Summary 写真アップロード枠の発行
+// @Description 写真をストレージに直接アップロードするための署名付きURLとキーを発行します。返されたヘッダーを付けて uploadUrl に PUT した後、料理の作成・更新・写真追加で photoKey を指定してください
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param body body UploadRequest true "アップロードする写真"
+// @Success 201 {object} UploadSlot
+// @Failure 400 {object} ErrorResponse
+// @Router /uploads [post]
+func PostUpload(w http.ResponseWriter, r *http.Request) {
+	var req UploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "リクエスト", "JSONの解析に失敗しました")
+		return
+	}
+
+	if validationErrors := validateUploadRequest(req); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Errors: validationErrors})
+		return
+	}
+
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "ストレージクライアントの取得に失敗しました")
+		return
+	}
+
+	slot := UploadSlot{
+		PhotoKey: generateSecureFileName(req.Filename),
+		Method:   http.MethodPut,
+		Headers: map[string]string{
+			"Content-Type":         req.ContentType,
+			utils.UploadSizeHeader: utils.UploadSizeRange(req.Size),
+		},
+		ExpiresAt: time.Now().Add(uploadSlotLifetime),
+	}
+	// 申告されたサイズちょうどのファイルのみアップロードできるようにする
+	slot.UploadURL, err = gcsClient.CreateSignedURL(r.Context(), slot.PhotoKey, req.ContentType, req.Size, uploadURLExpiration)
+	if err != nil {
+		writeServerError(w, r, err, "ストレージ", "署名付きURL生成に失敗しました")
+		return
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "データベース接続に失敗しました")
+		return
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(r.Context(),
+		`INSERT INTO upload_slots (object_name, content_type, size, actor, expires_at) VALUES ($1, $2, $3, $4, $5)`,
+		slot.PhotoKey, req.ContentType, req.Size, actorFromRequest(r), slot.ExpiresAt,
+	)
+	if err != nil {
+		writeServerError(w, r, err, "データベース", "アップロード枠の登録に失敗しました")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(slot)
+}
+
+// receivedPhoto 受け取った写真
+type receivedPhoto struct {
+	objectName string // 料理に保存するオブジェクト名
+	photoKey   string // 直接アップロードされた写真のアップロード枠のキー（フォームで送信された場合は空）
+}
+
+// fromSlot 直接アップロードされた写真かどうか
+func (p receivedPhoto) fromSlot() bool {
+	return p.photoKey != ""
+}
+
+// receivePhoto フォームの料理の写真（公開する写真）を受け取る
+// photoKey が指定された場合は直接アップロードされた写真を確認し、
+// photo ファイルが送信された場合はGCSにアップロードする。写真がない場合は objectName が空になる
+// エラー時はレスポンスを書き込み ok が false になる
+func receivePhoto(ctx context.Context, w http.ResponseWriter, r *http.Request, q db.Querier, gcsClient *utils.GCSClient, required bool) (photo receivedPhoto, ok bool) {
+	if photoKey := strings.TrimSpace(r.FormValue("photoKey")); photoKey != "" {
+		objectName, err := receiveUpload(ctx, q, gcsClient, photoKey, true)
+		if err != nil {
+			writeUploadError(w, r, err)
+			return receivedPhoto{}, false
+		}
+		return receivedPhoto{objectName: objectName, photoKey: photoKey}, true
+	}
+
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		if required {
+			writeErrorResponse(w, http.StatusBadRequest, "写真", "写真ファイルまたは photoKey が指定されていません")
+			return receivedPhoto{}, false
+		}
+		return receivedPhoto{}, true
+	}
+	defer file.Close()
+
+	objectName, err := uploadFormPhoto(w, r, gcsClient, file, header, true)
+	return receivedPhoto{objectName: objectName}, err == nil
+}
+
+// receiveUpload 直接アップロードされた写真を確認し、保存先のオブジェクト名を返す
+// 公開する写真をハッシュ名で保存する場合は、アップロード枠のキーとは別のオブジェクト名になる
+func receiveUpload(ctx context.Context, q db.Querier, gcsClient *utils.GCSClient, photoKey string, public bool) (string, error) {
+	if err := verifyUpload(ctx, q, gcsClient, photoKey); err != nil {
+		return "", err
+	}
+	return storeUploadedPhoto(ctx, gcsClient, photoKey, public)
+}
+
+// releaseUpload 登録が完了した直接アップロードの写真について、保存先にコピーした元のファイルを削除する
+// アップロード枠は登録時に削除済みのため、削除に失敗した場合はログに残す
+func releaseUpload(ctx context.Context, gcsClient *utils.GCSClient, photo receivedPhoto) {
+	if !photo.fromSlot() || photo.photoKey == photo.objectName {
+		return
+	}
+	if err := gcsClient.DeleteFile(ctx, photo.photoKey); err != nil {
+		slog.ErrorContext(ctx, "アップロードされた写真の削除に失敗しました", "object", photo.photoKey, "error", err)
+	}
+}
+
+// uploadFormPhoto フォームで送信された写真ファイルの形式とサイズを確認してGCSにアップロードする
+// エラー時はレスポンスを書き込む
+func uploadFormPhoto(w http.ResponseWriter, r *http.Request, gcsClient *utils.GCSClient, file multipart.File, header *multipart.FileHeader, public bool) (string, error) {
+	if !isValidImageFormat(header.Filename) {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています")
+		return "", errors.New("invalid image format")
+	}
+	if header.Size > maxPhotoSize {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", fmt.Sprintf("写真は%dMBまでアップロードできます", maxPhotoSize>>20))
+		return "", errors.New("photo too large")
+	}
+
+	objectName, err := uploadPhoto(r.Context(), gcsClient, file, header, public)
+	if err != nil {
+		writeServerError(w, r, err, "写真", "写真のアップロードに失敗しました")
+		return "", err
+	}
+	return objectName, nil
+}
+
+// verifyUpload 直接アップロードされた写真が、発行したアップロード枠のとおりにストレージに保存されているか確認する
+// 形式・サイズが不正なファイルは削除する
+func verifyUpload(ctx context.Context, q db.Querier, gcsClient *utils.GCSClient, photoKey string) error {
+	var contentType string
+	var expectedSize *int64
+	err := q.QueryRow(ctx,
+		`SELECT content_type, size FROM upload_slots WHERE object_name = $1 AND expires_at > now()`,
+		photoKey,
+	).Scan(&contentType, &expectedSize)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &uploadError{message: "アップロード枠が見つからないか、有効期限が切れています"}
+	}
+	if err != nil {
+		return err
+	}
+
+	exists, err := gcsClient.FileExists(ctx, photoKey)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &uploadError{message: "写真がアップロードされていません"}
+	}
+
+	storedType, size, err := gcsClient.FileAttrs(ctx, photoKey)
+	if err != nil {
+		return err
+	}
+	head, err := gcsClient.ReadFileHead(ctx, photoKey, 512)
+	if err != nil {
+		return err
+	}
+	if size > maxPhotoSize || (expectedSize != nil && size != *expectedSize) ||
+		storedType != contentType || http.DetectContentType(head) != contentType {
+		gcsClient.DeleteFile(ctx, photoKey)
+		return &uploadError{message: "アップロードされたファイルの形式またはサイズが不正です。もう一度アップロードしてください"}
+	}
+
+	return nil
+}
+
+// consumeUpload アップロード枠を使用済みにする（料理の登録と同じトランザクションで呼ぶ）
+func consumeUpload(ctx context.Context, q db.Querier, photoKey string) error {
+	result, err := q.Exec(ctx, `DELETE FROM upload_slots WHERE object_name = $1 AND expires_at > now()`, photoKey)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return &uploadError{message: "アップロード枠が見つからないか、有効期限が切れています"}
+	}
+	return nil
+}
+
+// uploadMethod 写真のアップロード方法（メトリクスのラベル）
+func uploadMethod(fromSlot bool) string {
+	if fromSlot {
+		return "direct"
+	}
+	return "form"
+}
+
+// writeUploadError 写真の確認エラーをレスポンスに書き込む
+func writeUploadError(w http.ResponseWriter, r *http.Request, err error) {
+	var uploadErr *uploadError
+	if errors.As(err, &uploadErr) {
+		writeErrorResponse(w, http.StatusBadRequest, "写真", uploadErr.message)
+		return
+	}
+	writeServerError(w, r, err, "写真", "アップロードされた写真の確認に失敗しました")
+}
@@ -3,6 +3,7 @@ package admin
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -10,38 +11,167 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/smilemasa/go-api/i18n"
+	"github.com/smilemasa/go-api/model"
+	"github.com/smilemasa/go-api/pricing"
+	"github.com/smilemasa/go-api/search"
 )
 
 // CreateDishRequest バリデーション用のリクエスト構造体
 type CreateDishRequest struct {
-	NameJa string `validate:"required,min=1,max=100" json:"nameJa"`
-	NameEn string `validate:"required,min=1,max=100" json:"nameEn"`
-	Price  int    `validate:"required,min=1" json:"price"`
+	NameJa    string   `validate:"required,min=1,max=100" json:"nameJa"`
+	NameEn    string   `validate:"omitempty,max=100" json:"nameEn"` // 省略時は日本語名を機械翻訳した下書きを作成する
+	Reading   string   `validate:"max=100,kana" json:"reading"`
+	Price     int      `validate:"required,min=1" json:"price"`
+	Category  string   `validate:"max=50" json:"category"`
+	Allergens []string `validate:"max=28,dive,allergen" json:"allergens"`
+	DishDetailsRequest
 }
 
 // UpdateDishRequest 更新用のリクエスト構造体
 type UpdateDishRequest struct {
-	NameJa string `validate:"omitempty,min=1,max=100" json:"nameJa"`
-	NameEn string `validate:"omitempty,min=1,max=100" json:"nameEn"`
-	Price  int    `validate:"omitempty,min=1" json:"price"`
+	NameJa    string   `validate:"omitempty,min=1,max=100" json:"nameJa"`
+	NameEn    string   `validate:"omitempty,min=1,max=100" json:"nameEn"`
+	Reading   string   `validate:"max=100,kana" json:"reading"`
+	Price     int      `validate:"omitempty,min=1" json:"price"`
+	Category  string   `validate:"max=50" json:"category"`
+	Allergens []string `validate:"max=28,dive,allergen" json:"allergens"`
+	DishDetailsRequest
 }
 
-// ValidationError バリデーションエラーの詳細
-type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
+// DishDetailsRequest 料理の詳細情報（作成・更新共通、栄養成分は未登録の場合 nil）
+type DishDetailsRequest struct {
+	DescriptionJa string   `validate:"max=2000" json:"descriptionJa"`
+	DescriptionEn string   `validate:"max=2000" json:"descriptionEn"`
+	Ingredients   []string `validate:"max=50,dive,max=100" json:"ingredients"`
+	Calories      *int     `validate:"omitempty,min=0,max=10000" json:"calories"`
+	Protein       *float64 `validate:"omitempty,min=0,max=1000" json:"protein"`
+	Fat           *float64 `validate:"omitempty,min=0,max=1000" json:"fat"`
+	Carbohydrate  *float64 `validate:"omitempty,min=0,max=1000" json:"carbohydrate"`
+	SpiceLevel    int      `validate:"min=0,max=5" json:"spiceLevel"`
+	PortionSize   string   `validate:"max=50" json:"portionSize"`
 }
 
-// ErrorResponse エラーレスポンス
-type ErrorResponse struct {
-	Errors []ValidationError `json:"errors"`
+// UploadRequest 写真のアップロード枠の発行用のリクエスト構造体
+type UploadRequest struct {
+	Filename    string `validate:"required,max=255" json:"filename"`
+	ContentType string `validate:"required,oneof=image/jpeg image/png image/webp" json:"contentType"`
+	Size        int64  `validate:"required,min=1" json:"size"`
+}
+
+// PhotoOrderRequest 写真の並び替え用のリクエスト構造体（料理のすべての写真IDを表示順に指定）
+type PhotoOrderRequest struct {
+	PhotoIDs []string `validate:"required,min=1,dive,uuid" json:"photoIds"`
+}
+
+// ModifierGroupRequest オプショングループ作成・更新用のリクエスト構造体
+type ModifierGroupRequest struct {
+	NameJa        string                  `validate:"required,min=1,max=100" json:"nameJa"`
+	NameEn        string                  `validate:"required,min=1,max=100" json:"nameEn"`
+	SelectionType string                  `validate:"required,oneof=single multi" json:"selectionType"`
+	MinChoices    int                     `validate:"min=0" json:"minChoices"`
+	MaxChoices    int                     `validate:"min=1" json:"maxChoices"`
+	Required      bool                    `json:"required"`
+	SortOrder     int                     `validate:"min=0" json:"sortOrder"`
+	Options       []ModifierOptionRequest `validate:"required,min=1,max=50,dive" json:"options"`
+}
+
+// ModifierOptionRequest オプション選択肢のリクエスト構造体
+type ModifierOptionRequest struct {
+	NameJa     string `validate:"required,min=1,max=100" json:"nameJa"`
+	NameEn     string `validate:"required,min=1,max=100" json:"nameEn"`
+	PriceDelta int    `validate:"min=-100000,max=100000" json:"priceDelta"`
+	SortOrder  int    `validate:"min=0" json:"sortOrder"`
+}
+
+// BundleRequest セットメニュー作成・更新用のリクエスト構造体
+type BundleRequest struct {
+	NameJa string              `validate:"required,min=1,max=100" json:"nameJa"`
+	NameEn string              `validate:"required,min=1,max=100" json:"nameEn"`
+	Price  int                 `validate:"required,min=1" json:"price"`
+	Slots  []BundleSlotRequest `validate:"required,min=1,max=10,dive" json:"slots"`
+}
+
+// BundleSlotRequest セットの構成枠のリクエスト構造体
+type BundleSlotRequest struct {
+	NameJa        string                `validate:"required,min=1,max=100" json:"nameJa"`
+	NameEn        string                `validate:"required,min=1,max=100" json:"nameEn"`
+	DefaultDishID string                `validate:"required" json:"defaultDishId"`
+	SortOrder     int                   `validate:"min=0" json:"sortOrder"`
+	Choices       []BundleChoiceRequest `validate:"max=50,dive" json:"choices"`
+}
+
+// BundleChoiceRequest 構成枠で選択可能な料理のリクエスト構造体
+type BundleChoiceRequest struct {
+	DishID     string `validate:"required" json:"dishId"`
+	PriceDelta int    `validate:"min=-100000,max=100000" json:"priceDelta"`
 }
 
+// PriceChangeRequest 価格変更予約用のリクエスト構造体
+type PriceChangeRequest struct {
+	Price       int       `validate:"required,min=1" json:"price"`
+	EffectiveAt time.Time `validate:"required" json:"effectiveAt"`
+}
+
+// PriceRuleRequest 価格ルール作成・更新用のリクエスト構造体
+// 対象は料理ID（dishId）またはカテゴリ（category）のどちらか一方を指定する
+type PriceRuleRequest struct {
+	Name           string `validate:"required,min=1,max=100" json:"name"`
+	DishID         string `json:"dishId"`
+	Category       string `validate:"max=50" json:"category"`
+	DaysOfWeek     []int  `validate:"max=7,dive,min=0,max=6" json:"daysOfWeek"`
+	StartTime      string `validate:"required" json:"startTime"`
+	EndTime        string `validate:"required" json:"endTime"`
+	StartsOn       string `json:"startsOn"`
+	EndsOn         string `json:"endsOn"`
+	AdjustmentType string `validate:"required,oneof=fixed percent amount" json:"adjustmentType"`
+	Value          int    `validate:"min=0" json:"value"`
+	Active         *bool  `json:"active"`
+}
+
+// TranslationRequest 料理の翻訳登録・更新用のリクエスト構造体
+type TranslationRequest struct {
+	Name        string `validate:"required,min=1,max=100" json:"name"`
+	Description string `validate:"max=2000" json:"description"`
+}
+
+// GlossaryEntryRequest 翻訳の用語集の登録・更新用のリクエスト構造体
+type GlossaryEntryRequest struct {
+	Term        string `validate:"required,min=1,max=100" json:"term"`
+	Locale      string `validate:"required" json:"locale"`
+	Translation string `validate:"required,min=1,max=100" json:"translation"`
+}
+
+// SearchDishesRequest 料理検索のクエリパラメータ
+type SearchDishesRequest struct {
+	Q                string   `validate:"max=100" json:"q"`
+	NameJa           string   `validate:"max=100" json:"nameJa"`
+	NameEn           string   `validate:"max=100" json:"nameEn"`
+	Category         string   `validate:"max=50" json:"category"`
+	MinPrice         *int     `validate:"omitempty,min=0" json:"minPrice"`
+	MaxPrice         *int     `validate:"omitempty,min=0" json:"maxPrice"`
+	ExcludeAllergens []string `validate:"dive,allergen" json:"excludeAllergens"`
+}
+
+// ValidationError バリデーションエラーの詳細
+type ValidationError = model.ValidationError
+
+// ErrorResponse エラーレスポンス
+type ErrorResponse = model.ErrorResponse
+
 // バリデーターインスタンス
 var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	// ふりがなはひらがな・カタカナのみ
+	validate.RegisterValidation("kana", func(fl validator.FieldLevel) bool {
+		return search.IsKana(fl.Field().String())
+	})
+	// アレルゲンは model.Allergen* のコードのみ
+	validate.RegisterValidation("allergen", func(fl validator.FieldLevel) bool {
+		return model.IsAllergen(fl.Field().String())
+	})
 }
 
 // validateCreateDishRequest 作成時のリクエストデータのバリデーション
@@ -63,9 +193,16 @@ func validateCreateDishRequest(req CreateDishRequest) []ValidationError {
 				}
 			case "max":
 				message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+			case "kana":
+				message = "ひらがなまたはカタカナで入力してください"
+			case "allergen":
+				message = fmt.Sprintf("%s は対応していないアレルゲンです", err.Value())
 			default:
 				message = "不正な値です"
 			}
+			if detailMessage, ok := dishDetailMessage(err); ok {
+				message = detailMessage
+			}
 
 			fieldName := getFieldName(err.Field())
 			errors = append(errors, ValidationError{
@@ -82,12 +219,6 @@ func validateCreateDishRequest(req CreateDishRequest) []ValidationError {
 			Message: "空白のみの入力は無効です",
 		})
 	}
-	if strings.TrimSpace(req.NameEn) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "料理名（英語）",
-			Message: "空白のみの入力は無効です",
-		})
-	}
 
 	return errors
 }
@@ -109,9 +240,16 @@ func validateUpdateDishRequest(req UpdateDishRequest) []ValidationError {
 				}
 			case "max":
 				message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+			case "kana":
+				message = "ひらがなまたはカタカナで入力してください"
+			case "allergen":
+				message = fmt.Sprintf("%s は対応していないアレルゲンです", err.Value())
 			default:
 				message = "不正な値です"
 			}
+			if detailMessage, ok := dishDetailMessage(err); ok {
+				message = detailMessage
+			}
 
 			fieldName := getFieldName(err.Field())
 			errors = append(errors, ValidationError{
@@ -138,15 +276,757 @@ func validateUpdateDishRequest(req UpdateDishRequest) []ValidationError {
 	return errors
 }
 
+// validateSearchDishesRequest 料理検索のクエリパラメータのバリデーション
+func validateSearchDishesRequest(req SearchDishesRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "min":
+				message = "価格は0円以上で指定してください"
+			case "max":
+				message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+			case "allergen":
+				message = fmt.Sprintf("%s は対応していないアレルゲンです", err.Value())
+			default:
+				message = "不正な値です"
+			}
+
+			errors = append(errors, ValidationError{
+				Field:   getSearchFieldName(err.Field()),
+				Message: message,
+			})
+		}
+	}
+
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		errors = append(errors, ValidationError{
+			Field:   "最高価格",
+			Message: "最高価格は最低価格以上で指定してください",
+		})
+	}
+
+	if req.Q == "" && req.NameJa == "" && req.NameEn == "" && req.Category == "" &&
+		req.MinPrice == nil && req.MaxPrice == nil && len(req.ExcludeAllergens) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "検索条件",
+			Message: "検索条件を1つ以上指定してください",
+		})
+	}
+
+	return errors
+}
+
+// getSearchFieldName 検索パラメータのフィールド名を日本語に変換
+func getSearchFieldName(field string) string {
+	if strings.HasPrefix(field, "ExcludeAllergens") {
+		return "除外アレルゲン"
+	}
+	switch field {
+	case "Q":
+		return "キーワード"
+	case "MinPrice":
+		return "最低価格"
+	case "MaxPrice":
+		return "最高価格"
+	default:
+		return getFieldName(field)
+	}
+}
+
+// validateModifierGroupRequest オプショングループのリクエストデータのバリデーション
+func validateModifierGroupRequest(req ModifierGroupRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "required":
+				message = "この項目は必須です"
+			case "oneof":
+				message = "single または multi を指定してください"
+			case "min":
+				switch err.Field() {
+				case "Options":
+					message = "選択肢を1つ以上登録してください"
+				case "NameJa", "NameEn":
+					message = fmt.Sprintf("最低%s文字以上入力してください", err.Param())
+				default:
+					message = fmt.Sprintf("%s以上の値を指定してください", err.Param())
+				}
+			case "max":
+				switch err.Field() {
+				case "Options":
+					message = fmt.Sprintf("選択肢は最大%s件までです", err.Param())
+				case "NameJa", "NameEn":
+					message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+				default:
+					message = fmt.Sprintf("%s以下の値を指定してください", err.Param())
+				}
+			default:
+				message = "不正な値です"
+			}
+
+			errors = append(errors, ValidationError{
+				Field:   getModifierFieldName(err.Namespace()),
+				Message: message,
+			})
+		}
+	}
+
+	// 選択数の整合性チェック
+	if req.SelectionType == model.SelectionSingle && req.MaxChoices != 1 {
+		errors = append(errors, ValidationError{
+			Field:   "最大選択数",
+			Message: "単一選択の場合、最大選択数は1である必要があります",
+		})
+	}
+	if req.MinChoices > req.MaxChoices {
+		errors = append(errors, ValidationError{
+			Field:   "最小選択数",
+			Message: "最小選択数は最大選択数以下である必要があります",
+		})
+	}
+	if len(req.Options) > 0 && req.MaxChoices > len(req.Options) {
+		errors = append(errors, ValidationError{
+			Field:   "最大選択数",
+			Message: "最大選択数は選択肢の数以下である必要があります",
+		})
+	}
+	if req.Required && req.MinChoices < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "最小選択数",
+			Message: "必須グループの場合、最小選択数は1以上である必要があります",
+		})
+	}
+
+	// 空白のみの名称チェック
+	if req.NameJa != "" && strings.TrimSpace(req.NameJa) == "" {
+		errors = append(errors, ValidationError{
+			Field:   "グループ名（日本語）",
+			Message: "空白のみの入力は無効です",
+		})
+	}
+	if req.NameEn != "" && strings.TrimSpace(req.NameEn) == "" {
+		errors = append(errors, ValidationError{
+			Field:   "グループ名（英語）",
+			Message: "空白のみの入力は無効です",
+		})
+	}
+	for i, opt := range req.Options {
+		if (opt.NameJa != "" && strings.TrimSpace(opt.NameJa) == "") ||
+			(opt.NameEn != "" && strings.TrimSpace(opt.NameEn) == "") {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("選択肢[%d]", i+1),
+				Message: "空白のみの入力は無効です",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateBundleRequest セットメニューのリクエストデータのバリデーション
+// 既定の料理が選択肢に含まれていない場合は差額0円の選択肢として追加する
+func validateBundleRequest(req *BundleRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "required":
+				message = "この項目は必須です"
+			case "min":
+				switch err.Field() {
+				case "Price":
+					message = "価格は1円以上である必要があります"
+				case "Slots":
+					message = "構成枠を1つ以上登録してください"
+				case "NameJa", "NameEn":
+					message = fmt.Sprintf("最低%s文字以上入力してください", err.Param())
+				default:
+					message = fmt.Sprintf("%s以上の値を指定してください", err.Param())
+				}
+			case "max":
+				switch err.Field() {
+				case "Slots":
+					message = fmt.Sprintf("構成枠は最大%s件までです", err.Param())
+				case "Choices":
+					message = fmt.Sprintf("選択肢は最大%s件までです", err.Param())
+				case "NameJa", "NameEn":
+					message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+				default:
+					message = fmt.Sprintf("%s以下の値を指定してください", err.Param())
+				}
+			default:
+				message = "不正な値です"
+			}
+
+			errors = append(errors, ValidationError{
+				Field:   getBundleFieldName(err.Namespace()),
+				Message: message,
+			})
+		}
+	}
+
+	if req.NameJa != "" && strings.TrimSpace(req.NameJa) == "" {
+		errors = append(errors, ValidationError{
+			Field:   "セット名（日本語）",
+			Message: "空白のみの入力は無効です",
+		})
+	}
+	if req.NameEn != "" && strings.TrimSpace(req.NameEn) == "" {
+		errors = append(errors, ValidationError{
+			Field:   "セット名（英語）",
+			Message: "空白のみの入力は無効です",
+		})
+	}
+
+	for i := range req.Slots {
+		slot := &req.Slots[i]
+		field := fmt.Sprintf("構成枠[%d]", i+1)
+
+		seen := map[string]bool{}
+		hasDefault := false
+		for _, c := range slot.Choices {
+			if seen[c.DishID] {
+				errors = append(errors, ValidationError{
+					Field:   field,
+					Message: "同じ料理が選択肢に重複しています",
+				})
+				break
+			}
+			seen[c.DishID] = true
+			if c.DishID == slot.DefaultDishID {
+				hasDefault = true
+				if c.PriceDelta != 0 {
+					errors = append(errors, ValidationError{
+						Field:   field,
+						Message: "既定の料理の差額は0円である必要があります",
+					})
+				}
+			}
+		}
+
+		if !hasDefault && slot.DefaultDishID != "" {
+			slot.Choices = append([]BundleChoiceRequest{{DishID: slot.DefaultDishID}}, slot.Choices...)
+		}
+	}
+
+	return errors
+}
+
+// validatePriceChangeRequest 価格変更予約のリクエストデータのバリデーション
+func validatePriceChangeRequest(req PriceChangeRequest) []ValidationError {
+	var errors []ValidationError
+
+	if req.Price < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "価格",
+			Message: "価格は1円以上である必要があります",
+		})
+	}
+	if req.EffectiveAt.IsZero() {
+		errors = append(errors, ValidationError{
+			Field:   "適用開始日時",
+			Message: "この項目は必須です",
+		})
+	} else if !req.EffectiveAt.After(time.Now()) {
+		errors = append(errors, ValidationError{
+			Field:   "適用開始日時",
+			Message: "適用開始日時は未来の日時を指定してください",
+		})
+	}
+
+	return errors
+}
+
+// validateTranslationRequest 翻訳のリクエストデータのバリデーション
+func validateTranslationRequest(req TranslationRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "required":
+				message = "この項目は必須です"
+			case "min":
+				message = fmt.Sprintf("最低%s文字以上入力してください", err.Param())
+			case "max":
+				message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+			default:
+				message = "不正な値です"
+			}
+
+			fieldName := "料理名"
+			if err.Field() == "Description" {
+				fieldName = "説明"
+			}
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Message: message,
+			})
+		}
+	}
+
+	if req.Name != "" && strings.TrimSpace(req.Name) == "" {
+		errors = append(errors, ValidationError{
+			Field:   "料理名",
+			Message: "空白のみの入力は無効です",
+		})
+	}
+
+	return errors
+}
+
+// validateUploadRequest アップロード枠の発行のリクエストデータのバリデーション
+func validateUploadRequest(req UploadRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "required":
+				message = "この項目は必須です"
+			case "max":
+				message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+			case "oneof":
+				message = "image/jpeg、image/png、image/webp のいずれかを指定してください"
+			case "min":
+				message = "1バイト以上を指定してください"
+			default:
+				message = "不正な値です"
+			}
+
+			fieldName := err.Field()
+			switch err.Field() {
+			case "Filename":
+				fieldName = "ファイル名"
+			case "ContentType":
+				fieldName = "ファイル形式"
+			case "Size":
+				fieldName = "ファイルサイズ"
+			}
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Message: message,
+			})
+		}
+	}
+
+	if req.Filename != "" {
+		if !isValidImageFormat(req.Filename) {
+			errors = append(errors, ValidationError{
+				Field:   "ファイル名",
+				Message: "対応していないファイル形式です。jpg、jpeg、png、webpのみ対応しています",
+			})
+		} else if req.ContentType != "" && getContentType(req.Filename) != req.ContentType {
+			errors = append(errors, ValidationError{
+				Field:   "ファイル形式",
+				Message: "ファイル名の拡張子とファイル形式が一致しません",
+			})
+		}
+	}
+	if req.Size > maxPhotoSize {
+		errors = append(errors, ValidationError{
+			Field:   "ファイルサイズ",
+			Message: fmt.Sprintf("写真は%dMBまでアップロードできます", maxPhotoSize>>20),
+		})
+	}
+
+	return errors
+}
+
+// validatePhotoOrderRequest 写真の並び替えのリクエストデータのバリデーション
+func validatePhotoOrderRequest(req PhotoOrderRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "required", "min":
+				message = "写真IDを1件以上指定してください"
+			case "uuid":
+				message = fmt.Sprintf("%s は不正な写真IDです", err.Value())
+			default:
+				message = "不正な値です"
+			}
+
+			errors = append(errors, ValidationError{
+				Field:   "写真ID",
+				Message: message,
+			})
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, id := range req.PhotoIDs {
+		if seen[id] {
+			errors = append(errors, ValidationError{
+				Field:   "写真ID",
+				Message: fmt.Sprintf("%s が重複しています", id),
+			})
+		}
+		seen[id] = true
+	}
+
+	return errors
+}
+
+// validateGlossaryEntryRequest 用語集のリクエストデータのバリデーション
+func validateGlossaryEntryRequest(req GlossaryEntryRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "required":
+				message = "この項目は必須です"
+			case "min":
+				message = fmt.Sprintf("最低%s文字以上入力してください", err.Param())
+			case "max":
+				message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+			default:
+				message = "不正な値です"
+			}
+
+			errors = append(errors, ValidationError{
+				Field:   getGlossaryFieldName(err.Field()),
+				Message: message,
+			})
+		}
+	}
+
+	if req.Locale != "" {
+		if locale, ok := i18n.Canonical(req.Locale); !ok || locale == i18n.Japanese {
+			errors = append(errors, ValidationError{
+				Field:   "ロケール",
+				Message: "対応していないロケールです",
+			})
+		}
+	}
+
+	for _, v := range []struct{ field, value string }{{"用語", req.Term}, {"訳語", req.Translation}} {
+		if v.value != "" && strings.TrimSpace(v.value) == "" {
+			errors = append(errors, ValidationError{
+				Field:   v.field,
+				Message: "空白のみの入力は無効です",
+			})
+		}
+	}
+
+	return errors
+}
+
+// getGlossaryFieldName 用語集のフィールド名を日本語に変換
+func getGlossaryFieldName(field string) string {
+	switch field {
+	case "Term":
+		return "用語"
+	case "Locale":
+		return "ロケール"
+	case "Translation":
+		return "訳語"
+	default:
+		return field
+	}
+}
+
+// validatePriceRuleRequest 価格ルールのリクエストデータのバリデーション
+func validatePriceRuleRequest(req PriceRuleRequest) []ValidationError {
+	var errors []ValidationError
+
+	err := validate.Struct(req)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var message string
+			switch err.Tag() {
+			case "required":
+				message = "この項目は必須です"
+			case "oneof":
+				message = "fixed、percent、amount のいずれかを指定してください"
+			case "min":
+				if err.Field() == "Name" {
+					message = fmt.Sprintf("最低%s文字以上入力してください", err.Param())
+				} else {
+					message = fmt.Sprintf("%s以上の値を指定してください", err.Param())
+				}
+			case "max":
+				switch err.Field() {
+				case "Name", "Category":
+					message = fmt.Sprintf("最大%s文字以下で入力してください", err.Param())
+				case "DaysOfWeek":
+					message = fmt.Sprintf("曜日は最大%s件までです", err.Param())
+				default:
+					message = fmt.Sprintf("%s以下の値を指定してください", err.Param())
+				}
+			default:
+				message = "不正な値です"
+			}
+
+			errors = append(errors, ValidationError{
+				Field:   getPriceRuleFieldName(err.Field()),
+				Message: message,
+			})
+		}
+	}
+
+	// 対象は料理かカテゴリのどちらか一方
+	if (req.DishID == "") == (strings.TrimSpace(req.Category) == "") {
+		errors = append(errors, ValidationError{
+			Field:   "対象",
+			Message: "料理IDまたはカテゴリのどちらか一方を指定してください",
+		})
+	}
+
+	// 時刻の形式チェック
+	start, startErr := pricing.ParseClock(req.StartTime)
+	if req.StartTime != "" && startErr != nil {
+		errors = append(errors, ValidationError{
+			Field:   "開始時刻",
+			Message: "HH:MM 形式で入力してください",
+		})
+	}
+	end, endErr := pricing.ParseClock(req.EndTime)
+	if req.EndTime != "" && endErr != nil {
+		errors = append(errors, ValidationError{
+			Field:   "終了時刻",
+			Message: "HH:MM 形式で入力してください",
+		})
+	}
+	if startErr == nil && endErr == nil && start == end {
+		errors = append(errors, ValidationError{
+			Field:   "終了時刻",
+			Message: "開始時刻と終了時刻には異なる時刻を指定してください",
+		})
+	}
+
+	// 適用期間のチェック
+	var startsOn, endsOn time.Time
+	if req.StartsOn != "" {
+		if startsOn, err = time.Parse("2006-01-02", req.StartsOn); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "適用開始日",
+				Message: "YYYY-MM-DD 形式で入力してください",
+			})
+		}
+	}
+	if req.EndsOn != "" {
+		if endsOn, err = time.Parse("2006-01-02", req.EndsOn); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "適用終了日",
+				Message: "YYYY-MM-DD 形式で入力してください",
+			})
+		}
+	}
+	if !startsOn.IsZero() && !endsOn.IsZero() && endsOn.Before(startsOn) {
+		errors = append(errors, ValidationError{
+			Field:   "適用終了日",
+			Message: "適用終了日は適用開始日以降の日付を指定してください",
+		})
+	}
+
+	// 調整値のチェック
+	switch req.AdjustmentType {
+	case model.AdjustmentFixed:
+		if req.Value < 1 {
+			errors = append(errors, ValidationError{
+				Field:   "調整値",
+				Message: "固定価格は1円以上である必要があります",
+			})
+		}
+	case model.AdjustmentPercent:
+		if req.Value < 1 || req.Value > 100 {
+			errors = append(errors, ValidationError{
+				Field:   "調整値",
+				Message: "割引率は1〜100の範囲で指定してください",
+			})
+		}
+	case model.AdjustmentAmount:
+		if req.Value < 1 {
+			errors = append(errors, ValidationError{
+				Field:   "調整値",
+				Message: "割引額は1円以上である必要があります",
+			})
+		}
+	}
+
+	return errors
+}
+
+// getPriceRuleFieldName 価格ルールのフィールド名を日本語に変換
+func getPriceRuleFieldName(field string) string {
+	switch field {
+	case "Name":
+		return "ルール名"
+	case "Category":
+		return "カテゴリ"
+	case "DaysOfWeek":
+		return "曜日"
+	case "StartTime":
+		return "開始時刻"
+	case "EndTime":
+		return "終了時刻"
+	case "AdjustmentType":
+		return "調整方法"
+	case "Value":
+		return "調整値"
+	default:
+		if strings.HasPrefix(field, "DaysOfWeek[") {
+			return "曜日"
+		}
+		return field
+	}
+}
+
+// getBundleFieldName セットメニューのフィールド名を日本語に変換
+// 例: "BundleRequest.Slots[0].Choices[1].DishID" → "構成枠[1]の選択肢[2]の料理ID"
+func getBundleFieldName(namespace string) string {
+	parts := strings.Split(namespace, ".")
+
+	var labels []string
+	for _, part := range parts[1:] {
+		var index int
+		switch {
+		case strings.HasPrefix(part, "Slots["):
+			fmt.Sscanf(part, "Slots[%d]", &index)
+			labels = append(labels, fmt.Sprintf("構成枠[%d]", index+1))
+		case strings.HasPrefix(part, "Choices["):
+			fmt.Sscanf(part, "Choices[%d]", &index)
+			labels = append(labels, fmt.Sprintf("選択肢[%d]", index+1))
+		case part == "NameJa":
+			labels = append(labels, "名称（日本語）")
+		case part == "NameEn":
+			labels = append(labels, "名称（英語）")
+		case part == "Price":
+			labels = append(labels, "価格")
+		case part == "Slots":
+			labels = append(labels, "構成枠")
+		case part == "Choices":
+			labels = append(labels, "選択肢")
+		case part == "DefaultDishID":
+			labels = append(labels, "既定の料理ID")
+		case part == "DishID":
+			labels = append(labels, "料理ID")
+		case part == "PriceDelta":
+			labels = append(labels, "差額")
+		case part == "SortOrder":
+			labels = append(labels, "表示順")
+		default:
+			labels = append(labels, part)
+		}
+	}
+
+	return strings.Join(labels, "の")
+}
+
+// getModifierFieldName オプショングループのフィールド名を日本語に変換
+// 例: "ModifierGroupRequest.Options[0].NameJa" → "選択肢[1]の名称（日本語）"
+func getModifierFieldName(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	field := parts[len(parts)-1]
+
+	var name string
+	switch field {
+	case "NameJa":
+		name = "名称（日本語）"
+	case "NameEn":
+		name = "名称（英語）"
+	case "SelectionType":
+		name = "選択方式"
+	case "MinChoices":
+		name = "最小選択数"
+	case "MaxChoices":
+		name = "最大選択数"
+	case "SortOrder":
+		name = "表示順"
+	case "PriceDelta":
+		name = "価格差分"
+	case "Options":
+		return "選択肢"
+	default:
+		name = field
+	}
+
+	// 選択肢のフィールドの場合はインデックスを付与
+	if len(parts) >= 3 {
+		var index int
+		if _, err := fmt.Sscanf(parts[len(parts)-2], "Options[%d]", &index); err == nil {
+			return fmt.Sprintf("選択肢[%d]の%s", index+1, name)
+		}
+	}
+
+	if field == "NameJa" || field == "NameEn" {
+		return "グループ" + name
+	}
+	return name
+}
+
+// dishDetailMessage 詳細情報の数値・件数のエラーメッセージ（文字数以外の制約）
+func dishDetailMessage(err validator.FieldError) (string, bool) {
+	switch {
+	case err.Field() == "Ingredients" && err.Tag() == "max":
+		return fmt.Sprintf("最大%s件まで登録できます", err.Param()), true
+	case err.Field() == "Calories" || err.Field() == "Protein" || err.Field() == "Fat" || err.Field() == "Carbohydrate" || err.Field() == "SpiceLevel":
+		if err.Tag() == "min" {
+			return fmt.Sprintf("%s以上で入力してください", err.Param()), true
+		}
+		return fmt.Sprintf("%s以下で入力してください", err.Param()), true
+	default:
+		return "", false
+	}
+}
+
 // getFieldName フィールド名を日本語に変換
 func getFieldName(field string) string {
+	if strings.HasPrefix(field, "Allergens") {
+		return "アレルゲン"
+	}
+	if strings.HasPrefix(field, "Ingredients") {
+		return "原材料"
+	}
 	switch field {
 	case "NameJa":
 		return "料理名（日本語）"
 	case "NameEn":
 		return "料理名（英語）"
+	case "Reading":
+		return "ふりがな"
 	case "Price":
 		return "価格"
+	case "Category":
+		return "カテゴリ"
+	case "DescriptionJa":
+		return "説明（日本語）"
+	case "DescriptionEn":
+		return "説明（英語）"
+	case "Calories":
+		return "エネルギー"
+	case "Protein":
+		return "たんぱく質"
+	case "Fat":
+		return "脂質"
+	case "Carbohydrate":
+		return "炭水化物"
+	case "SpiceLevel":
+		return "辛さ"
+	case "PortionSize":
+		return "量"
 	default:
 		return field
 	}
@@ -188,3 +1068,10 @@ func writeErrorResponse(w http.ResponseWriter, statusCode int, field, message st
 		},
 	})
 }
+
+// writeServerError 500 エラーの原因をリクエストIDとともにログに出力し、エラーレスポンスを書き込む
+// （レスポンスには原因の詳細を含めない）
+func writeServerError(w http.ResponseWriter, r *http.Request, err error, field, message string) {
+	slog.ErrorContext(r.Context(), message, "field", field, "error", err)
+	writeErrorResponse(w, http.StatusInternalServerError, field, message)
+}
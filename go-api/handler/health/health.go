@@ -0,0 +1,162 @@
+// Package health Cloud Run や Kubernetes のプローブ用のヘルスチェックハンドラー
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smilemasa/go-api/config"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// checkTimeout 依存サービスごとの確認のタイムアウト（応答しない依存サービスでプローブが止まらないようにする）
+const checkTimeout = 2 * time.Second
+
+// 確認結果の状態
+const (
+	StatusOK          = "ok"
+	StatusError       = "error"
+	StatusSkipped     = "skipped"
+	StatusUnavailable = "unavailable"
+)
+
+// errSkipped 設定されていない依存サービスのため確認しない
+var errSkipped = errors.New("not configured")
+
+// Check 依存サービスごとの確認結果
+// 認証なしで公開するため状態のみを返し、エラー内容や処理時間はログに出力する
+type Check struct {
+	Status string `json:"status"` // ok、error または skipped（設定されていないため確認しない）
+}
+
+// Response ヘルスチェックのレスポンス
+type Response struct {
+	Status string           `json:"status"`           // ok または unavailable
+	Checks map[string]Check `json:"checks,omitempty"` // 依存サービスごとの確認結果（readyz のみ）
+}
+
+// checks readyz で確認する依存サービス
+var checks = map[string]func(ctx context.Context) error{
+	"database":   db.Ping,
+	"storage":    pingStorage,
+	"migrations": checkMigrations,
+}
+
+// 生存確認ハンドラー
+// @Summary 生存確認
+// @Description プロセスが動作していれば常に200を返します（依存サービスは確認しません）
+// @Tags health
+// @Produce json
+// @Success 200 {object} Response
+// @Router /healthz [get]
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, http.StatusOK, Response{Status: StatusOK})
+}
+
+// 準備完了確認ハンドラー
+// @Summary 準備完了確認
+// @Description データベース・ストレージに接続でき、マイグレーションがすべて適用済みかを確認します。いずれかが失敗した場合は503を返します（失敗の詳細はログに出力します）。ストレージが設定されていない場合は skipped になります
+// @Tags health
+// @Produce json
+// @Success 200 {object} Response
+// @Failure 503 {object} Response
+// @Router /readyz [get]
+func Readyz(w http.ResponseWriter, r *http.Request) {
+	resp := Response{Status: StatusOK, Checks: make(map[string]Check, len(checks))}
+
+	// 依存サービスは並行して確認する
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := runCheck(r.Context(), name, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[name] = result
+			if result.Status != StatusOK {
+				resp.Status = StatusUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+
+	statusCode := http.StatusOK
+	if resp.Status != StatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+	writeResponse(w, statusCode, resp)
+}
+
+// runCheck タイムアウト付きで確認を実行する
+// 確認が ctx を無視して応答しない場合もタイムアウトで打ち切る。失敗した場合は詳細をログに出力する
+func runCheck(ctx context.Context, name string, check func(ctx context.Context) error) Check {
+	logCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	latencyMs := float64(time.Since(start).Microseconds()) / 1000
+	switch {
+	case errors.Is(err, errSkipped):
+		return Check{Status: StatusSkipped}
+	case err != nil:
+		slog.WarnContext(logCtx, "準備完了確認で依存サービスの確認に失敗しました", "check", name, "latency_ms", latencyMs, "error", err)
+		return Check{Status: StatusError}
+	}
+	return Check{Status: StatusOK}
+}
+
+// pingStorage ストレージのバケットにアクセスできるか確認する（バケットが設定されていない場合は確認しない）
+func pingStorage(ctx context.Context) error {
+	if config.Get().GCS.BucketName == "" {
+		return errSkipped
+	}
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		return err
+	}
+	return gcsClient.Ping(ctx)
+}
+
+// checkMigrations 未適用のマイグレーションがないか確認する
+func checkMigrations(ctx context.Context) error {
+	pending, err := db.PendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// writeResponse ヘルスチェックの結果を書き込む（プローブの結果はキャッシュさせない）
+func writeResponse(w http.ResponseWriter, statusCode int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resp)
+}
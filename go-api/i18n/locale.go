@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// 対応するロケール
+const (
+	Japanese           = "ja"
+	English            = "en"
+	SimplifiedChinese  = "zh-Hans"
+	TraditionalChinese = "zh-Hant"
+	Korean             = "ko"
+)
+
+// Default ロケールが判定できない場合に使うロケール
+const Default = Japanese
+
+// Supported 対応するロケールの一覧（先頭が既定）
+var Supported = []string{Japanese, English, SimplifiedChinese, TraditionalChinese, Korean}
+
+// fallbacks ロケールごとの代替の順序（訳がない場合に次に使うロケール）
+var fallbacks = map[string][]string{
+	Japanese:           {English},
+	English:            {Japanese},
+	SimplifiedChinese:  {TraditionalChinese, English, Japanese},
+	TraditionalChinese: {SimplifiedChinese, English, Japanese},
+	Korean:             {English, Japanese},
+}
+
+var matcher = language.NewMatcher(func() []language.Tag {
+	tags := make([]language.Tag, len(Supported))
+	for i, l := range Supported {
+		tags[i] = language.MustParse(l)
+	}
+	return tags
+}())
+
+// Negotiate Accept-Language ヘッダーの値から対応するロケールを選ぶ
+// zh-TW・zh-HK は zh-Hant、zh-CN は zh-Hans として扱う
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return Supported[index]
+}
+
+// Canonical ロケール文字列を対応するロケールの表記に揃える（大文字・小文字、_ と - の違いを吸収）
+func Canonical(locale string) (string, bool) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	for _, l := range Supported {
+		if strings.EqualFold(l, locale) {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// FallbackChain 指定したロケールと、訳がない場合に順に使うロケールを返す
+func FallbackChain(locale string) []string {
+	return append([]string{locale}, fallbacks[locale]...)
+}
+
+// IsBuiltin 料理の日本語名・英語名として dishes テーブルに保存されるロケールかどうか
+func IsBuiltin(locale string) bool {
+	return locale == Japanese || locale == English
+}
@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", Japanese},
+		{"ja-JP,ja;q=0.9", Japanese},
+		{"en-US,en;q=0.9,ja;q=0.8", English},
+		{"zh-CN", SimplifiedChinese},
+		{"zh-TW", TraditionalChinese},
+		{"zh-HK", TraditionalChinese},
+		{"zh-Hant", TraditionalChinese},
+		{"ko-KR", Korean},
+		{"fr-FR,en;q=0.5", English},
+		{"fr-FR", Default},
+		{"ja;q=0.2,ko;q=0.9", Korean},
+		{";;q=invalid", Default},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.acceptLanguage); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"ja", Japanese, true},
+		{"EN", English, true},
+		{"zh_hant", TraditionalChinese, true},
+		{" zh-HANS ", SimplifiedChinese, true},
+		{"zh", "", false},
+		{"fr", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Canonical(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Canonical(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{Japanese, []string{Japanese, English}},
+		{English, []string{English, Japanese}},
+		{SimplifiedChinese, []string{SimplifiedChinese, TraditionalChinese, English, Japanese}},
+		{TraditionalChinese, []string{TraditionalChinese, SimplifiedChinese, English, Japanese}},
+		{Korean, []string{Korean, English, Japanese}},
+		{"fr", []string{"fr"}},
+	}
+	for _, tt := range tests {
+		if got := FallbackChain(tt.locale); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FallbackChain(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+
+	// 返り値を変更しても代替の順序は変わらない
+	chain := FallbackChain(Korean)
+	chain[1] = "changed"
+	if got := FallbackChain(Korean); got[1] != English {
+		t.Errorf("FallbackChain(%q) after modifying a previous result = %q", Korean, got)
+	}
+}
@@ -0,0 +1,72 @@
+// Package logging log/slog による構造化ログの設定と、リクエストIDの受け渡しを行う
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Format ログの出力形式
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Setup 標準のロガーを設定する
+// 出力するログにはコンテキストのリクエストID（request_id）とトレースID（trace_id, span_id）を付ける
+func Setup(level slog.Level, format string) {
+	slog.SetDefault(slog.New(NewHandler(os.Stdout, level, format)))
+}
+
+// NewHandler 指定したレベル・形式で w に出力するハンドラーを作成する
+func NewHandler(w io.Writer, level slog.Level, format string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	if format == FormatText {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return contextHandler{Handler: h}
+}
+
+// contextHandler コンテキストのリクエストIDとトレースIDをログに付けるハンドラー
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		record.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID リクエストIDを持つコンテキストを返す
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID コンテキストのリクエストIDを返す（ない場合は空文字）
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
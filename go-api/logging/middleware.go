@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader リクエストIDを受け渡すヘッダー
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength 受け付けるリクエストIDの最大長（これより長い場合は新しく発行する）
+const maxRequestIDLength = 128
+
+// maxErrorBodyLength アクセスログに含めるエラーレスポンスの最大長
+const maxErrorBodyLength = 1024
+
+// Middleware リクエストIDの発行とアクセスログの出力を行うミドルウェア
+// リクエストIDは X-Request-Id ヘッダーで受け取り（ない場合は発行）、レスポンスヘッダーとコンテキストに設定する
+// エラーレスポンス（4xx/5xx）はレスポンスの内容もログに出力する
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := WithRequestID(r.Context(), id)
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		switch {
+		case rec.status >= 500:
+			level = slog.LevelError
+		case rec.status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Int64("bytes", rec.bytes),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.String("user_agent", r.UserAgent()),
+		}
+		if rec.errorBody.Len() > 0 {
+			attrs = append(attrs, slog.String("error", strings.TrimSpace(rec.errorBody.String())))
+		}
+		slog.LogAttrs(ctx, level, "request", attrs...)
+	})
+}
+
+// validRequestID クライアントから受け取ったリクエストIDをそのまま使えるかどうか
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// responseRecorder アクセスログ用にステータスコードとレスポンスサイズを記録する
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+	errorBody   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.status >= 400 && r.errorBody.Len() < maxErrorBodyLength {
+		r.errorBody.Write(b[:min(len(b), maxErrorBodyLength-r.errorBody.Len())])
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap http.ResponseController から元の ResponseWriter を使えるようにする
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -10,70 +10,145 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata" // コンテナにタイムゾーンデータがない環境でも価格ルールの時刻判定を行えるようにする
 
 	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
 	"github.com/rs/cors"
+	"github.com/smilemasa/go-api/auth"
 	"github.com/smilemasa/go-api/config"
 	"github.com/smilemasa/go-api/db"
 	dishes "github.com/smilemasa/go-api/handler/admin/dishes"
+	"github.com/smilemasa/go-api/handler/health"
+	"github.com/smilemasa/go-api/logging"
+	"github.com/smilemasa/go-api/metrics"
+	"github.com/smilemasa/go-api/tracing"
 	"github.com/smilemasa/go-api/utils"
+	"github.com/smilemasa/go-api/worker"
 )
 
-func init() {
-	// .envファイルを読み込む
-	if err := godotenv.Load(); err != nil {
-		fmt.Println("Error loading .env file")
+func main() {
+	// config print: 読み込んだ設定を秘密情報を伏せて出力する（設定の確認用）
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := run(); err != nil {
+		slog.Error("アプリケーションを終了します", "error", err)
+		os.Exit(1)
 	}
 }
 
-func main() {
+// run アプリケーションを起動し、終了シグナル（SIGINT, SIGTERM）を受け取るまでリクエストを処理する
+// 終了時は処理中のリクエストを待ってから、ワーカー・ストレージ・データベース・トレースの順に停止する
+// （Cloud Run の猶予期間10秒に収まるよう、各段階の待ち時間には上限を設ける）
+func run() error {
 	// 設定を読み込む
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Printf("Failed to load config: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// 設定したレベル・形式で構造化ログを出力する
+	logging.Setup(cfg.Log.Level, cfg.Log.Format)
+	slog.Info("Config loaded successfully")
+
+	// トレースの出力先を設定
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
 	}
-	fmt.Println("Config loaded successfully")
+	defer func() {
+		// 送信待ちのスパンを出力してから終了する
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownStepTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Error shutting down tracing", "error", err)
+		}
+	}()
 
 	// データベース疎通確認
 	if err := db.TestConnection(); err != nil {
-		fmt.Printf("❌ データベース疎通確認失敗: %v\n", err)
-		fmt.Println("アプリケーションを終了します")
-		os.Exit(1)
+		return fmt.Errorf("データベース疎通確認失敗: %w", err)
+	}
+
+	// マイグレーションを適用
+	if err := db.Migrate(context.Background()); err != nil {
+		return fmt.Errorf("マイグレーション失敗: %w", err)
 	}
+	// アプリケーション終了時にデータベースの接続プールを閉じる（使用中の接続が返されるのを待ちすぎない）
+	defer waitShutdownStep("database pool", db.ClosePool)
 
 	// GCSクライアントを初期化
 	bucketName := cfg.GCS.BucketName
 	if bucketName == "" {
-		fmt.Println("Warning: GCS_BUCKET_NAME not set in configuration")
+		slog.Warn("GCS_BUCKET_NAME not set in configuration")
 	} else {
 		ctx := context.Background()
-		if err := utils.InitGCSClient(ctx, bucketName); err != nil {
-			fmt.Printf("Failed to initialize GCS client: %v\n", err)
-			os.Exit(1)
+		urlOptions := utils.SignedURLOptions{
+			TTL:          cfg.GCS.SignedURLTTL,
+			MinRemaining: cfg.GCS.SignedURLMinRemaining,
+			CacheSize:    cfg.GCS.SignedURLCacheSize,
+			Concurrency:  cfg.GCS.SignConcurrency,
+		}
+		if err := utils.InitGCSClient(ctx, bucketName, urlOptions); err != nil {
+			return fmt.Errorf("failed to initialize GCS client: %w", err)
 		}
-		fmt.Println("GCS client initialized successfully")
+		slog.Info("GCS client initialized successfully")
 
 		// アプリケーション終了時にGCSクライアントを閉じる
 		defer func() {
 			if err := utils.Close(); err != nil {
-				fmt.Printf("Error closing GCS client: %v\n", err)
+				slog.Error("Error closing GCS client", "error", err)
 			}
 		}()
 	}
 
+	// ゴミ箱の料理を定期的に完全削除
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	purgerDone := worker.StartDishPurger(workerCtx, cfg.Trash.Retention, cfg.Trash.PurgeInterval)
+	// 使われなかった直接アップロードの写真を定期的に削除
+	// （GCS未設定の場合はアップロード枠を発行できず削除する写真もないため起動しない）
+	var cleanerDone <-chan struct{}
+	if bucketName != "" {
+		cleanerDone = worker.StartUploadSlotCleaner(workerCtx, time.Hour)
+	}
+	// 終了時はストレージ・データベースを閉じる前にワーカーの停止を待つ
+	defer func() {
+		stopWorkers()
+		waitShutdownStep("background workers", func() {
+			<-purgerDone
+			if cleanerDone != nil {
+				<-cleanerDone
+			}
+		})
+	}()
+
 	r := mux.NewRouter()
+	// ルートごとのリクエスト数と処理時間を記録し、スパン名をルートテンプレートにする
+	r.Use(metrics.Middleware, tracing.RouteMiddleware)
 
-	// CORS設定 - 環境変数から設定を取得
-	allowedOrigins := getCORSOrigins()
-	isDevelopment := os.Getenv("ENVIRONMENT") != "production"
-	fmt.Printf("CORS allowed origins: %v\n", allowedOrigins)
+	// 管理APIのトークン認証（ヘルスチェックと画像配信は認証なしで呼べるようにする）
+	credentials := cfg.Auth.Credentials()
+	if len(credentials) == 0 {
+		slog.Warn("ADMIN_API_TOKEN and ADMIN_API_USER_TOKENS not set; admin API is not authenticated")
+	}
+	r.Use(auth.Middleware(credentials, "/healthz", "/readyz", "/images/"))
+
+	// CORS設定
+	slog.Info("CORS allowed origins", "origins", cfg.CORS.AllowedOrigins)
 	c := cors.New(cors.Options{
-		AllowedOrigins: allowedOrigins,
+		AllowedOrigins: cfg.CORS.AllowedOrigins,
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders: []string{
 			"Accept",
@@ -81,61 +156,156 @@ func main() {
 			"Content-Type",
 			"X-CSRF-Token",
 			"X-Requested-With",
+			logging.RequestIDHeader,
+			"X-User-Id",
+			// フロントエンドからトレースを引き継ぐ（W3C Trace Context）
+			"traceparent",
+			"tracestate",
+		},
+		ExposedHeaders: []string{
+			"X-Did-You-Mean",
+			logging.RequestIDHeader,
 		},
 		AllowCredentials: true,
-		Debug:            isDevelopment, // 開発環境でのみデバッグ有効
+		Debug:            !cfg.IsProduction(), // 開発環境でのみデバッグ有効
 	})
 
-	// CORSミドルウェアを適用
-	handler := c.Handler(r)
+	// CORSミドルウェアを適用し、リクエストIDの発行とアクセスログの出力を行う
+	// トレースはアクセスログにトレースIDを含めるため最も外側で開始する
+	handler := tracing.Middleware(logging.Middleware(c.Handler(r)))
 
 	// Routes
+	// Prometheus のメトリクス
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
+	// Cloud Run・Kubernetes のプローブ用ヘルスチェック
+	r.HandleFunc("/healthz", health.Healthz).Methods("GET")
+	r.HandleFunc("/readyz", health.Readyz).Methods("GET")
+
 	r.HandleFunc("/dishes", dishes.PostDish).Methods("POST")
 	r.HandleFunc("/dishes", dishes.AdminGetDishes).Methods("GET")
 
 	r.HandleFunc("/dishes/search", dishes.SearchDishes).Methods("GET")
+	r.HandleFunc("/dishes/suggest", dishes.SuggestDishes).Methods("GET")
+	r.HandleFunc("/dishes/trash", dishes.AdminGetDeletedDishes).Methods("GET")
+	r.HandleFunc("/dishes/import", dishes.ImportDishes).Methods("POST")
+	r.HandleFunc("/dishes/export", dishes.ExportDishes).Methods("GET")
 
 	r.HandleFunc("/dishes/{id}", dishes.AdminGetDish).Methods("GET")
 	r.HandleFunc("/dishes/{id}", dishes.PutDish).Methods("PUT")
 	r.HandleFunc("/dishes/{id}", dishes.DeleteDish).Methods("DELETE")
+	r.HandleFunc("/dishes/{id}/restore", dishes.RestoreDish).Methods("POST")
+
+	r.HandleFunc("/dishes/{id}/modifier-groups", dishes.GetModifierGroups).Methods("GET")
+	r.HandleFunc("/dishes/{id}/modifier-groups", dishes.PostModifierGroup).Methods("POST")
+	r.HandleFunc("/dishes/{id}/modifier-groups/{groupId}", dishes.PutModifierGroup).Methods("PUT")
+	r.HandleFunc("/dishes/{id}/modifier-groups/{groupId}", dishes.DeleteModifierGroup).Methods("DELETE")
+
+	r.HandleFunc("/uploads", dishes.PostUpload).Methods("POST")
+	r.HandleFunc("/images/{name}", dishes.GetImage).Methods("GET")
+	r.HandleFunc("/dishes/{id}/photos", dishes.GetDishPhotos).Methods("GET")
+	r.HandleFunc("/dishes/{id}/photos", dishes.PostDishPhotos).Methods("POST")
+	r.HandleFunc("/dishes/{id}/photos/order", dishes.PutDishPhotoOrder).Methods("PUT")
+	r.HandleFunc("/dishes/{id}/photos/{photoId}/cover", dishes.PutDishCoverPhoto).Methods("PUT")
+	r.HandleFunc("/dishes/{id}/photos/{photoId}", dishes.DeleteDishPhoto).Methods("DELETE")
+
+	r.HandleFunc("/dishes/{id}/translations", dishes.GetDishTranslations).Methods("GET")
+	r.HandleFunc("/dishes/{id}/translations/{locale}", dishes.PutDishTranslation).Methods("PUT")
+	r.HandleFunc("/dishes/{id}/translations/{locale}", dishes.DeleteDishTranslation).Methods("DELETE")
+	r.HandleFunc("/translations/missing", dishes.GetMissingTranslations).Methods("GET")
+	r.HandleFunc("/dishes/{id}/translation-drafts/{locale}/approve", dishes.ApproveTranslationDraft).Methods("POST")
+	r.HandleFunc("/dishes/{id}/translation-drafts/{locale}", dishes.DeleteTranslationDraft).Methods("DELETE")
+	r.HandleFunc("/translations/drafts", dishes.GetTranslationDrafts).Methods("GET")
+	r.HandleFunc("/translations/glossary", dishes.GetGlossary).Methods("GET")
+	r.HandleFunc("/translations/glossary", dishes.PostGlossaryEntry).Methods("POST")
+	r.HandleFunc("/translations/glossary/{id}", dishes.DeleteGlossaryEntry).Methods("DELETE")
+
+	r.HandleFunc("/dishes/{id}/revisions", dishes.GetDishRevisions).Methods("GET")
+	r.HandleFunc("/dishes/{id}/revisions/{revisionId}/restore", dishes.RestoreDishRevision).Methods("POST")
+
+	r.HandleFunc("/dishes/{id}/price-changes", dishes.GetPriceChanges).Methods("GET")
+	r.HandleFunc("/dishes/{id}/price-changes", dishes.PostPriceChange).Methods("POST")
+	r.HandleFunc("/dishes/{id}/price-changes/{changeId}", dishes.DeletePriceChange).Methods("DELETE")
 
-	fmt.Println("🚀 Listening on http://localhost:8080")
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080" // ローカル用のデフォルト
+	r.HandleFunc("/price-rules", dishes.GetPriceRules).Methods("GET")
+	r.HandleFunc("/price-rules", dishes.PostPriceRule).Methods("POST")
+	r.HandleFunc("/price-rules/{id}", dishes.PutPriceRule).Methods("PUT")
+	r.HandleFunc("/price-rules/{id}", dishes.DeletePriceRule).Methods("DELETE")
+
+	r.HandleFunc("/bundles", dishes.PostBundle).Methods("POST")
+	r.HandleFunc("/bundles", dishes.AdminGetBundles).Methods("GET")
+	r.HandleFunc("/bundles/{id}", dishes.AdminGetBundle).Methods("GET")
+	r.HandleFunc("/bundles/{id}", dishes.PutBundle).Methods("PUT")
+	r.HandleFunc("/bundles/{id}", dishes.DeleteBundle).Methods("DELETE")
+	r.HandleFunc("/bundles/{id}/quote", dishes.QuoteBundle).Methods("POST")
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
 	}
-	http.ListenAndServe(":"+port, handler)
-}
 
-// getCORSOrigins 環境変数からCORSの許可オリジンを取得
-func getCORSOrigins() []string {
-	// 環境変数からオリジンを取得（カンマ区切り）
-	originsEnv := os.Getenv("CORS_ALLOWED_ORIGINS")
-
-	// 環境変数が設定されていない場合のデフォルト値
-	if originsEnv == "" {
-		environment := os.Getenv("ENVIRONMENT")
-		if environment == "production" {
-			// 本番環境のデフォルト（実際のドメインに変更してください）
-			return []string{
-				"https://your-production-domain.com",
-				"https://www.your-production-domain.com",
-			}
-		} else {
-			// 開発環境のデフォルト
-			return []string{
-				"http://localhost:3000",
-				"http://localhost:3001",
-				"http://127.0.0.1:3000",
-			}
-		}
+	// 終了シグナルを受け取ったら新しいリクエストの受け付けを止める
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "addr", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
 	}
+	stop()
 
-	// カンマ区切りの文字列をスライスに変換
-	origins := strings.Split(originsEnv, ",")
-	for i, origin := range origins {
-		origins[i] = strings.TrimSpace(origin)
+	// 処理中のリクエストが終わるまで待つ（期限を過ぎたら残りの接続を切断する）
+	slog.Info("Shutting down", "timeout", cfg.Server.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		return fmt.Errorf("failed to drain in-flight requests: %w", err)
 	}
+	slog.Info("Server stopped")
+
+	return nil
+}
+
+// shutdownStepTimeout リクエストの待機後の停止処理（ワーカー・データベース・トレース）それぞれの待ち時間の上限
+// 既定の server.shutdown_timeout（8秒）と合わせて、Cloud Run が SIGTERM の10秒後に強制終了するまでに収める
+const shutdownStepTimeout = 500 * time.Millisecond
+
+// waitShutdownStep 停止処理 fn の完了を shutdownStepTimeout まで待つ（待ちきれない場合は残りの停止処理に進む）
+func waitShutdownStep(name string, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
 
-	return origins
+	select {
+	case <-done:
+		slog.Info("Stopped", "component", name)
+	case <-time.After(shutdownStepTimeout):
+		slog.Warn("Gave up waiting for shutdown", "component", name, "timeout", shutdownStepTimeout)
+	}
+}
+
+// runCommand サブコマンドを実行する
+func runCommand(args []string) error {
+	if len(args) == 2 && args[0] == "config" && args[1] == "print" {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("invalid config:\n%w", err)
+		}
+		return cfg.WriteYAML(os.Stdout)
+	}
+	return fmt.Errorf("unknown command %q (usage: %s [config print])", strings.Join(args, " "), os.Args[0])
 }
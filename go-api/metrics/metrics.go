@@ -0,0 +1,158 @@
+// Package metrics Prometheus で収集するメトリクスを定義する
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace メトリクス名の接頭辞
+const namespace = "cookorder"
+
+var (
+	// HTTPRequestDuration ルート（mux のルートテンプレート）ごとのリクエスト処理時間
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestsInFlight 処理中のリクエスト数
+	HTTPRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	})
+
+	// DBQueryDuration SQLの種類（SELECT, INSERT など）ごとのクエリ実行時間
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by statement type and result.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "status"})
+
+	// StorageDuration ストレージ操作（upload, sign など）ごとの処理時間
+	StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Cloud Storage operation latency by operation and result.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"operation", "status"})
+
+	// DishesCreated 登録した料理の数（source は api または import）
+	DishesCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dishes_created_total",
+		Help:      "Number of dishes created, by source.",
+	}, []string{"source"})
+
+	// BundlesCreated 登録したセットメニューの数
+	BundlesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bundles_created_total",
+		Help:      "Number of bundles created.",
+	})
+
+	// BundleQuotes セットメニューの見積もり（構成品を選んだときの価格計算）の数
+	// 注文数ではない。このAPIには注文を確定するエンドポイントがないため、注文数（orders placed）は
+	// 注文を受け付けるサービス側で計測する必要があり、ここでは計測していない
+	BundleQuotes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bundle_quotes_total",
+		Help:      "Number of bundle price quotes calculated (not orders placed; orders are not handled by this API).",
+	})
+
+	// SignedURLCacheRequests 署名付きURLキャッシュの参照数（result は hit または miss）
+	SignedURLCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signed_url_cache_requests_total",
+		Help:      "Signed download URL cache lookups, by result.",
+	}, []string{"result"})
+
+	// SignedURLCacheEvictions 上限を超えて署名付きURLキャッシュから追い出したURLの数
+	SignedURLCacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signed_url_cache_evictions_total",
+		Help:      "Signed download URLs evicted from the cache because it was full.",
+	})
+
+	// PhotosUploaded 登録した写真の数（method は form または direct）
+	PhotosUploaded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "photos_uploaded_total",
+		Help:      "Number of dish photos registered, by upload method.",
+	}, []string{"method"})
+)
+
+// Handler /metrics で公開するハンドラー
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Status 処理結果のラベル（エラーの有無）
+func Status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// ObserveStorage ストレージ操作の処理時間を記録する
+func ObserveStorage(operation string, start time.Time, err error) {
+	StorageDuration.WithLabelValues(operation, Status(err)).Observe(time.Since(start).Seconds())
+}
+
+// Middleware ルートごとのリクエスト数と処理時間を記録する mux のミドルウェア
+// パスではなくルートテンプレート（/dishes/{id} など）をラベルに使う
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		HTTPRequestsInFlight.Inc()
+		defer HTTPRequestsInFlight.Dec()
+
+		route := "unknown"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tmpl, err := current.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		HTTPRequestDuration.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder レスポンスのステータスコードを記録する
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap http.ResponseController から元の ResponseWriter を使えるようにする
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
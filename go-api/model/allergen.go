@@ -0,0 +1,50 @@
+package model
+
+// アレルゲン（食品表示基準の特定原材料8品目と、特定原材料に準ずるもの20品目）
+const (
+	AllergenEgg       = "egg"       // 卵
+	AllergenMilk      = "milk"      // 乳
+	AllergenWheat     = "wheat"     // 小麦
+	AllergenBuckwheat = "buckwheat" // そば
+	AllergenPeanut    = "peanut"    // 落花生
+	AllergenShrimp    = "shrimp"    // えび
+	AllergenCrab      = "crab"      // かに
+	AllergenWalnut    = "walnut"    // くるみ
+
+	AllergenAlmond    = "almond"     // アーモンド
+	AllergenAbalone   = "abalone"    // あわび
+	AllergenSquid     = "squid"      // いか
+	AllergenSalmonRoe = "salmon_roe" // いくら
+	AllergenOrange    = "orange"     // オレンジ
+	AllergenCashew    = "cashew"     // カシューナッツ
+	AllergenKiwi      = "kiwi"       // キウイフルーツ
+	AllergenBeef      = "beef"       // 牛肉
+	AllergenSesame    = "sesame"     // ごま
+	AllergenSalmon    = "salmon"     // さけ
+	AllergenMackerel  = "mackerel"   // さば
+	AllergenSoybean   = "soybean"    // 大豆
+	AllergenChicken   = "chicken"    // 鶏肉
+	AllergenBanana    = "banana"     // バナナ
+	AllergenPork      = "pork"       // 豚肉
+	AllergenMatsutake = "matsutake"  // まつたけ
+	AllergenPeach     = "peach"      // もも
+	AllergenYam       = "yam"        // やまいも
+	AllergenApple     = "apple"      // りんご
+	AllergenGelatin   = "gelatin"    // ゼラチン
+)
+
+// allergens 登録可能なアレルゲン
+var allergens = map[string]bool{
+	AllergenEgg: true, AllergenMilk: true, AllergenWheat: true, AllergenBuckwheat: true,
+	AllergenPeanut: true, AllergenShrimp: true, AllergenCrab: true, AllergenWalnut: true,
+	AllergenAlmond: true, AllergenAbalone: true, AllergenSquid: true, AllergenSalmonRoe: true,
+	AllergenOrange: true, AllergenCashew: true, AllergenKiwi: true, AllergenBeef: true,
+	AllergenSesame: true, AllergenSalmon: true, AllergenMackerel: true, AllergenSoybean: true,
+	AllergenChicken: true, AllergenBanana: true, AllergenPork: true, AllergenMatsutake: true,
+	AllergenPeach: true, AllergenYam: true, AllergenApple: true, AllergenGelatin: true,
+}
+
+// IsAllergen 登録可能なアレルゲンかどうか
+func IsAllergen(code string) bool {
+	return allergens[code]
+}
@@ -0,0 +1,30 @@
+package model
+
+// Bundle セットメニュー
+type Bundle struct {
+	ID     string       `json:"id"`     // セットID
+	NameJa string       `json:"nameJa"` // 日本語名
+	NameEn string       `json:"nameEn"` // 英語名
+	Price  int          `json:"price"`  // セット価格（既定の構成での価格）
+	Img    string       `json:"img"`    // 画像URL
+	Slots  []BundleSlot `json:"slots"`  // 構成枠
+}
+
+// BundleSlot セットを構成する枠（メイン、サイド、ドリンクなど）
+type BundleSlot struct {
+	ID            string             `json:"id"`            // 枠ID
+	NameJa        string             `json:"nameJa"`        // 日本語名
+	NameEn        string             `json:"nameEn"`        // 英語名
+	DefaultDishID string             `json:"defaultDishId"` // 既定の料理ID
+	SortOrder     int                `json:"sortOrder"`     // 表示順
+	Choices       []BundleSlotChoice `json:"choices"`       // 選択可能な料理
+}
+
+// BundleSlotChoice 枠で選択可能な料理
+type BundleSlotChoice struct {
+	DishID     string `json:"dishId"`     // 料理ID
+	NameJa     string `json:"nameJa"`     // 料理の日本語名
+	NameEn     string `json:"nameEn"`     // 料理の英語名
+	DishPrice  int    `json:"dishPrice"`  // 料理の単品価格（価格変更・価格ルールを反映した実売価格）
+	PriceDelta int    `json:"priceDelta"` // セット価格に対する差額（円）
+}
@@ -1,9 +1,42 @@
 package model
 
+import "time"
+
 type Dish struct {
-	ID     string `json:"id"`     // 料理ID
-	NameJa string `json:"nameJa"` // 日本語名
-	NameEn string `json:"nameEn"` // 英語名
-	Price  int    `json:"price"`  // 価格
-	Img    string `json:"img"`    // 画像URL
+	ID             string          `json:"id"`                       // 料理ID
+	NameJa         string          `json:"nameJa"`                   // 日本語名
+	NameEn         string          `json:"nameEn"`                   // 英語名
+	Reading        string          `json:"reading"`                  // ふりがな（検索用）
+	Locale         string          `json:"locale,omitempty"`         // 表示ロケール（name・description のロケール）
+	Name           string          `json:"name,omitempty"`           // 表示ロケールの料理名（訳がない場合は代替ロケール）
+	Description    string          `json:"description,omitempty"`    // 表示ロケールの説明
+	Price          int             `json:"price"`                    // 価格（指定日時における実売価格）
+	BasePrice      int             `json:"basePrice"`                // 通常価格（価格ルール適用前）
+	PriceRuleID    string          `json:"priceRuleId,omitempty"`    // 適用された価格ルールID
+	Img            string          `json:"img"`                      // 画像URL
+	Category       string          `json:"category"`                 // カテゴリ
+	Allergens      []string        `json:"allergens"`                // アレルゲン（model.Allergen* のコード）
+	ModifierGroups []ModifierGroup `json:"modifierGroups,omitempty"` // オプショングループ（詳細取得時のみ）
+	Photos         []DishPhoto     `json:"photos,omitempty"`         // 写真（詳細取得時のみ）
+	DeletedAt      *time.Time      `json:"deletedAt,omitempty"`      // 削除日時（ゴミ箱の料理のみ）
+
+	DishDetails // 詳細情報（説明・原材料・栄養成分など）
+}
+
+// DishDetails 料理の詳細情報（説明・原材料・栄養成分・辛さ・量）
+type DishDetails struct {
+	DescriptionJa string    `json:"descriptionJa"` // 説明（日本語）
+	DescriptionEn string    `json:"descriptionEn"` // 説明（英語）
+	Ingredients   []string  `json:"ingredients"`   // 原材料
+	Nutrition     Nutrition `json:"nutrition"`     // 栄養成分（1食あたり）
+	SpiceLevel    int       `json:"spiceLevel"`    // 辛さ（0〜5、0は辛くない・5は激辛）
+	PortionSize   string    `json:"portionSize"`   // 量（例: 1人前 約300g）
+}
+
+// Nutrition 栄養成分（未登録の項目は null）
+type Nutrition struct {
+	Calories     *int     `json:"calories"`     // エネルギー（kcal）
+	Protein      *float64 `json:"protein"`      // たんぱく質（g）
+	Fat          *float64 `json:"fat"`          // 脂質（g）
+	Carbohydrate *float64 `json:"carbohydrate"` // 炭水化物（g）
 }
@@ -0,0 +1,12 @@
+package model
+
+// ValidationError バリデーションエラーの詳細
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse エラーレスポンス（APIのエラーはすべてこの形式で返す）
+type ErrorResponse struct {
+	Errors []ValidationError `json:"errors"`
+}
@@ -0,0 +1,30 @@
+package model
+
+// ModifierGroup 料理のオプショングループ（サイズ、辛さ、トッピングなど）
+type ModifierGroup struct {
+	ID            string           `json:"id"`            // グループID
+	DishID        string           `json:"dishId"`        // 料理ID
+	NameJa        string           `json:"nameJa"`        // 日本語名
+	NameEn        string           `json:"nameEn"`        // 英語名
+	SelectionType string           `json:"selectionType"` // 選択方式（single: 単一選択, multi: 複数選択）
+	MinChoices    int              `json:"minChoices"`    // 最小選択数
+	MaxChoices    int              `json:"maxChoices"`    // 最大選択数
+	Required      bool             `json:"required"`      // 選択必須かどうか
+	SortOrder     int              `json:"sortOrder"`     // 表示順
+	Options       []ModifierOption `json:"options"`       // 選択肢
+}
+
+// ModifierOption オプショングループ内の選択肢
+type ModifierOption struct {
+	ID         string `json:"id"`         // 選択肢ID
+	NameJa     string `json:"nameJa"`     // 日本語名
+	NameEn     string `json:"nameEn"`     // 英語名
+	PriceDelta int    `json:"priceDelta"` // 価格差分（円、値引きの場合は負数）
+	SortOrder  int    `json:"sortOrder"`  // 表示順
+}
+
+// 選択方式
+const (
+	SelectionSingle = "single"
+	SelectionMulti  = "multi"
+)
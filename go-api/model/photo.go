@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// DishPhoto 料理の写真（ギャラリー）
+type DishPhoto struct {
+	ID        string    `json:"id"`        // 写真ID
+	DishID    string    `json:"dishId"`    // 料理ID
+	URL       string    `json:"url"`       // 画像URL（署名付きURL）
+	SortOrder int       `json:"sortOrder"` // 表示順
+	IsCover   bool      `json:"isCover"`   // 代表写真かどうか（料理の img と同じ写真）
+	IsPublic  bool      `json:"isPublic"`  // 署名なしのURLで配信してよい写真かどうか（スタッフの顔写真などは false）
+	CreatedAt time.Time `json:"createdAt"` // 登録日時
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// PriceChange 予約された価格変更
+type PriceChange struct {
+	ID          string    `json:"id"`          // 価格変更ID
+	DishID      string    `json:"dishId"`      // 料理ID
+	Price       int       `json:"price"`       // 変更後の価格
+	EffectiveAt time.Time `json:"effectiveAt"` // 適用開始日時
+}
+
+// PriceRule 曜日・時間帯で繰り返し適用される価格ルール（ハッピーアワーなど）
+type PriceRule struct {
+	ID             string `json:"id"`                 // ルールID
+	Name           string `json:"name"`               // ルール名
+	DishID         string `json:"dishId,omitempty"`   // 対象の料理ID（料理単位のルール）
+	Category       string `json:"category,omitempty"` // 対象のカテゴリ（カテゴリ単位のルール）
+	DaysOfWeek     []int  `json:"daysOfWeek"`         // 適用曜日（0: 日曜 〜 6: 土曜、空の場合は毎日）
+	StartTime      string `json:"startTime"`          // 開始時刻（HH:MM）
+	EndTime        string `json:"endTime"`            // 終了時刻（HH:MM、開始時刻より前の場合は翌日にまたがる）
+	StartsOn       string `json:"startsOn,omitempty"` // 適用開始日（YYYY-MM-DD）
+	EndsOn         string `json:"endsOn,omitempty"`   // 適用終了日（YYYY-MM-DD）
+	AdjustmentType string `json:"adjustmentType"`     // 調整方法（fixed: 固定価格, percent: 割引率, amount: 割引額）
+	Value          int    `json:"value"`              // 調整値
+	Active         bool   `json:"active"`             // 有効かどうか
+}
+
+// 価格ルールの調整方法
+const (
+	AdjustmentFixed   = "fixed"
+	AdjustmentPercent = "percent"
+	AdjustmentAmount  = "amount"
+)
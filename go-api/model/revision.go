@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// DishRevision 料理の変更履歴
+type DishRevision struct {
+	ID        string        `json:"id"`        // 履歴ID
+	DishID    string        `json:"dishId"`    // 料理ID
+	Action    string        `json:"action"`    // 操作（create / update / delete / restore）
+	OldValues *DishSnapshot `json:"oldValues"` // 変更前の値（作成時は null）
+	NewValues *DishSnapshot `json:"newValues"` // 変更後の値（削除時は null）
+	Actor     string        `json:"actor"`     // 操作したユーザー
+	CreatedAt time.Time     `json:"createdAt"` // 操作日時
+}
+
+// DishSnapshot 履歴に記録する料理の値
+type DishSnapshot struct {
+	NameJa    string   `json:"nameJa"`    // 日本語名
+	NameEn    string   `json:"nameEn"`    // 英語名
+	Reading   string   `json:"reading"`   // ふりがな
+	Price     int      `json:"price"`     // 価格
+	Img       string   `json:"img"`       // 画像のオブジェクト名
+	Category  string   `json:"category"`  // カテゴリ
+	Allergens []string `json:"allergens"` // アレルゲン
+
+	DishDetails // 詳細情報（説明・原材料・栄養成分など）
+}
+
+// 履歴の操作種別
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// DishTranslation 料理名・説明の翻訳（日本語・英語以外のロケール）
+type DishTranslation struct {
+	DishID      string    `json:"dishId"`      // 料理ID
+	Locale      string    `json:"locale"`      // ロケール（zh-Hans, zh-Hant, ko など）
+	Name        string    `json:"name"`        // 料理名
+	Description string    `json:"description"` // 説明
+	UpdatedAt   time.Time `json:"updatedAt"`   // 更新日時
+}
+
+// MissingTranslations 翻訳が不足している料理
+type MissingTranslations struct {
+	DishID         string   `json:"dishId"`         // 料理ID
+	NameJa         string   `json:"nameJa"`         // 日本語名
+	NameEn         string   `json:"nameEn"`         // 英語名
+	MissingLocales []string `json:"missingLocales"` // 翻訳がないロケール
+}
+
+// TranslationDraft 機械翻訳で作成した料理名の下書き（承認されるまで表示には使わない）
+type TranslationDraft struct {
+	DishID    string    `json:"dishId"`    // 料理ID
+	NameJa    string    `json:"nameJa"`    // 翻訳元の日本語名
+	Locale    string    `json:"locale"`    // ロケール（en, zh-Hans, zh-Hant, ko など）
+	Name      string    `json:"name"`      // 翻訳された料理名
+	Current   string    `json:"current"`   // 現在の料理名（未登録の場合は空文字）
+	Source    string    `json:"source"`    // 翻訳バックエンド
+	CreatedAt time.Time `json:"createdAt"` // 作成日時
+}
+
+// GlossaryEntry 翻訳の用語集の項目
+type GlossaryEntry struct {
+	ID          string    `json:"id"`          // 用語ID
+	Term        string    `json:"term"`        // 日本語の用語
+	Locale      string    `json:"locale"`      // ロケール
+	Translation string    `json:"translation"` // 訳語
+	UpdatedAt   time.Time `json:"updatedAt"`   // 更新日時
+}
@@ -0,0 +1,96 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/smilemasa/go-api/model"
+)
+
+var (
+	// ErrUnknownSlot セットに存在しない枠が指定された
+	ErrUnknownSlot = errors.New("unknown bundle slot")
+	// ErrSubstitutionNotAllowed 枠で選択できない料理が指定された
+	ErrSubstitutionNotAllowed = errors.New("substitution not allowed")
+	// ErrDefaultDishUnavailable 料理の指定がない枠の既定の料理が選択肢にない（削除済みなど）
+	ErrDefaultDishUnavailable = errors.New("default dish unavailable")
+)
+
+// BundleComponent 価格算出に使われたセットの構成品
+type BundleComponent struct {
+	SlotID     string `json:"slotId"`     // 枠ID
+	DishID     string `json:"dishId"`     // 選択された料理ID
+	PriceDelta int    `json:"priceDelta"` // セット価格に対する差額
+	DishPrice  int    `json:"dishPrice"`  // 料理の単品価格
+}
+
+// BundleQuote セット価格の算出結果
+type BundleQuote struct {
+	BundleID     string            `json:"bundleId"`     // セットID
+	Price        int               `json:"price"`        // セット価格
+	RegularPrice int               `json:"regularPrice"` // 単品で注文した場合の合計価格
+	Savings      int               `json:"savings"`      // 単品合計との差額
+	Components   []BundleComponent `json:"components"`   // 構成品
+}
+
+// ResolveBundlePrice 選択された構成品からセット価格を算出する
+// selections は枠IDから料理IDへのマップで、指定のない枠は既定の料理として扱う
+func ResolveBundlePrice(b model.Bundle, selections map[string]string) (BundleQuote, error) {
+	slots := make(map[string]bool, len(b.Slots))
+	for _, slot := range b.Slots {
+		slots[slot.ID] = true
+	}
+	for slotID := range selections {
+		if !slots[slotID] {
+			return BundleQuote{}, fmt.Errorf("%w: %s", ErrUnknownSlot, slotID)
+		}
+	}
+
+	quote := BundleQuote{
+		BundleID:   b.ID,
+		Price:      b.Price,
+		Components: make([]BundleComponent, 0, len(b.Slots)),
+	}
+
+	for _, slot := range b.Slots {
+		dishID := slot.DefaultDishID
+		selected := selections[slot.ID] != ""
+		if selected {
+			dishID = selections[slot.ID]
+		}
+
+		choice, ok := findChoice(slot, dishID)
+		if !ok && !selected {
+			return BundleQuote{}, fmt.Errorf("%w: slot %s, dish %s", ErrDefaultDishUnavailable, slot.ID, dishID)
+		}
+		if !ok {
+			return BundleQuote{}, fmt.Errorf("%w: slot %s, dish %s", ErrSubstitutionNotAllowed, slot.ID, dishID)
+		}
+
+		quote.Price += choice.PriceDelta
+		quote.RegularPrice += choice.DishPrice
+		quote.Components = append(quote.Components, BundleComponent{
+			SlotID:     slot.ID,
+			DishID:     dishID,
+			PriceDelta: choice.PriceDelta,
+			DishPrice:  choice.DishPrice,
+		})
+	}
+
+	if quote.Price < 0 {
+		quote.Price = 0
+	}
+	quote.Savings = quote.RegularPrice - quote.Price
+
+	return quote, nil
+}
+
+// findChoice 枠の選択肢から料理を探す
+func findChoice(slot model.BundleSlot, dishID string) (model.BundleSlotChoice, bool) {
+	for _, c := range slot.Choices {
+		if c.DishID == dishID {
+			return c, true
+		}
+	}
+	return model.BundleSlotChoice{}, false
+}
@@ -0,0 +1,158 @@
+package pricing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/smilemasa/go-api/model"
+)
+
+func TestResolveBundlePrice(t *testing.T) {
+	bundle := model.Bundle{
+		ID:    "lunch",
+		Price: 1000,
+		Slots: []model.BundleSlot{
+			{
+				ID:            "main",
+				DefaultDishID: "karaage",
+				Choices: []model.BundleSlotChoice{
+					{DishID: "karaage", DishPrice: 700},
+					{DishID: "tonkatsu", DishPrice: 900, PriceDelta: 150},
+				},
+			},
+			{
+				ID:            "drink",
+				DefaultDishID: "tea",
+				Choices: []model.BundleSlotChoice{
+					{DishID: "tea", DishPrice: 300},
+					{DishID: "water", DishPrice: 0, PriceDelta: -2000},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		selections map[string]string
+		want       BundleQuote
+		wantErr    error
+	}{
+		{
+			name:       "default dishes",
+			selections: nil,
+			want: BundleQuote{
+				BundleID:     "lunch",
+				Price:        1000,
+				RegularPrice: 1000,
+				Savings:      0,
+				Components: []BundleComponent{
+					{SlotID: "main", DishID: "karaage", DishPrice: 700},
+					{SlotID: "drink", DishID: "tea", DishPrice: 300},
+				},
+			},
+		},
+		{
+			name:       "substitution adds price delta",
+			selections: map[string]string{"main": "tonkatsu"},
+			want: BundleQuote{
+				BundleID:     "lunch",
+				Price:        1150,
+				RegularPrice: 1200,
+				Savings:      50,
+				Components: []BundleComponent{
+					{SlotID: "main", DishID: "tonkatsu", PriceDelta: 150, DishPrice: 900},
+					{SlotID: "drink", DishID: "tea", DishPrice: 300},
+				},
+			},
+		},
+		{
+			name:       "empty selection uses default dish",
+			selections: map[string]string{"main": ""},
+			want: BundleQuote{
+				BundleID:     "lunch",
+				Price:        1000,
+				RegularPrice: 1000,
+				Components: []BundleComponent{
+					{SlotID: "main", DishID: "karaage", DishPrice: 700},
+					{SlotID: "drink", DishID: "tea", DishPrice: 300},
+				},
+			},
+		},
+		{
+			name:       "price does not go below zero",
+			selections: map[string]string{"drink": "water"},
+			want: BundleQuote{
+				BundleID:     "lunch",
+				Price:        0,
+				RegularPrice: 700,
+				Savings:      700,
+				Components: []BundleComponent{
+					{SlotID: "main", DishID: "karaage", DishPrice: 700},
+					{SlotID: "drink", DishID: "water", PriceDelta: -2000, DishPrice: 0},
+				},
+			},
+		},
+		{
+			name:       "unknown slot",
+			selections: map[string]string{"dessert": "pudding"},
+			wantErr:    ErrUnknownSlot,
+		},
+		{
+			name:       "dish not allowed in slot",
+			selections: map[string]string{"drink": "tonkatsu"},
+			wantErr:    ErrSubstitutionNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveBundlePrice(bundle, tt.selections)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("quote = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 既定の料理が削除されて選択肢から外れた枠（loadBundleSlots は削除済みの料理を選択肢に含めない）
+func TestResolveBundlePriceDefaultDishUnavailable(t *testing.T) {
+	bundle := model.Bundle{
+		ID:    "lunch",
+		Price: 1000,
+		Slots: []model.BundleSlot{{
+			ID:            "main",
+			DefaultDishID: "karaage",
+			Choices:       []model.BundleSlotChoice{{DishID: "tonkatsu", DishPrice: 900, PriceDelta: 150}},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		selections map[string]string
+		wantPrice  int
+		wantErr    error
+	}{
+		{name: "no selection", selections: nil, wantErr: ErrDefaultDishUnavailable},
+		{name: "empty selection", selections: map[string]string{"main": ""}, wantErr: ErrDefaultDishUnavailable},
+		{name: "explicit default", selections: map[string]string{"main": "karaage"}, wantErr: ErrSubstitutionNotAllowed},
+		{name: "other choice", selections: map[string]string{"main": "tonkatsu"}, wantPrice: 1150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := ResolveBundlePrice(bundle, tt.selections)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && quote.Price != tt.wantPrice {
+				t.Errorf("price = %d, want %d", quote.Price, tt.wantPrice)
+			}
+		})
+	}
+}
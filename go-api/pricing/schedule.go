@@ -0,0 +1,168 @@
+package pricing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smilemasa/go-api/model"
+)
+
+// Resolution 価格解決の結果
+type Resolution struct {
+	Price     int    // 実売価格
+	BasePrice int    // 通常価格（予約変更適用後、価格ルール適用前）
+	RuleID    string // 適用された価格ルールID（適用なしの場合は空）
+}
+
+// Resolver 指定日時における料理の実売価格を解決する
+type Resolver struct {
+	changes  map[string][]model.PriceChange
+	rules    []model.PriceRule
+	location *time.Location
+}
+
+// NewResolver 価格変更と価格ルールから Resolver を作成する
+// location はルールの曜日・時刻を判定するタイムゾーン
+func NewResolver(changes []model.PriceChange, rules []model.PriceRule, location *time.Location) *Resolver {
+	byDish := make(map[string][]model.PriceChange)
+	for _, c := range changes {
+		byDish[c.DishID] = append(byDish[c.DishID], c)
+	}
+	if location == nil {
+		location = time.Local
+	}
+	return &Resolver{changes: byDish, rules: rules, location: location}
+}
+
+// Resolve 料理の実売価格を解決する
+func (r *Resolver) Resolve(dishID, category string, storedPrice int, at time.Time) Resolution {
+	return EffectivePrice(dishID, category, storedPrice, r.changes[dishID], r.rules, at, r.location)
+}
+
+// EffectivePrice 指定日時における料理の実売価格を算出する
+//
+// 通常価格は at 以前で最も新しい予約変更の価格（なければ storedPrice）とし、
+// 有効な価格ルールのうち最も安くなるものを適用する。
+func EffectivePrice(dishID, category string, storedPrice int, changes []model.PriceChange, rules []model.PriceRule, at time.Time, location *time.Location) Resolution {
+	base := storedPrice
+	var latest time.Time
+	for _, c := range changes {
+		if c.DishID != dishID || c.EffectiveAt.After(at) {
+			continue
+		}
+		if latest.IsZero() || c.EffectiveAt.After(latest) {
+			latest = c.EffectiveAt
+			base = c.Price
+		}
+	}
+
+	res := Resolution{Price: base, BasePrice: base}
+	for _, rule := range rules {
+		if !ruleTargets(rule, dishID, category) || !RuleActiveAt(rule, at, location) {
+			continue
+		}
+		if price := applyRule(rule, base); price < res.Price {
+			res.Price = price
+			res.RuleID = rule.ID
+		}
+	}
+
+	return res
+}
+
+// RuleActiveAt 価格ルールが指定日時に有効かどうか
+// 終了時刻が開始時刻より前のルールは日付をまたぐものとして扱い、
+// 翌日分は開始日の曜日・期間で判定する
+func RuleActiveAt(rule model.PriceRule, at time.Time, location *time.Location) bool {
+	if !rule.Active {
+		return false
+	}
+
+	start, err := ParseClock(rule.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(rule.EndTime)
+	if err != nil {
+		return false
+	}
+
+	local := at.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return minute >= start && minute < end && ruleAppliesOn(rule, local)
+	}
+
+	// 日付をまたぐルール（例: 22:00〜02:00）
+	if minute >= start {
+		return ruleAppliesOn(rule, local)
+	}
+	if minute < end {
+		return ruleAppliesOn(rule, local.AddDate(0, 0, -1))
+	}
+	return false
+}
+
+// ParseClock "HH:MM" 形式の時刻を0時からの経過分に変換する
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", s, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ruleTargets ルールが料理に適用対象かどうか
+func ruleTargets(rule model.PriceRule, dishID, category string) bool {
+	if rule.DishID != "" {
+		return rule.DishID == dishID
+	}
+	return rule.Category != "" && rule.Category == category
+}
+
+// ruleAppliesOn ルールが指定日（曜日・適用期間）に適用されるかどうか
+func ruleAppliesOn(rule model.PriceRule, day time.Time) bool {
+	if len(rule.DaysOfWeek) > 0 {
+		matched := false
+		for _, d := range rule.DaysOfWeek {
+			if time.Weekday(d) == day.Weekday() {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	date := day.Format("2006-01-02")
+	if rule.StartsOn != "" && date < rule.StartsOn {
+		return false
+	}
+	if rule.EndsOn != "" && date > rule.EndsOn {
+		return false
+	}
+	return true
+}
+
+// applyRule 通常価格にルールの調整を適用する（0円未満にはしない）
+func applyRule(rule model.PriceRule, base int) int {
+	var price int
+	switch rule.AdjustmentType {
+	case model.AdjustmentFixed:
+		price = rule.Value
+	case model.AdjustmentPercent:
+		// 割引額の円未満は切り捨て
+		price = base - base*rule.Value/100
+	case model.AdjustmentAmount:
+		price = base - rule.Value
+	default:
+		return base
+	}
+
+	if price < 0 {
+		return 0
+	}
+	return price
+}
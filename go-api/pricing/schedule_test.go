@@ -0,0 +1,206 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smilemasa/go-api/model"
+)
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+// at 2026年10月の日時（JST）を返す（16日は金曜日、17日は土曜日）
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.October, day, hour, minute, 0, 0, jst)
+}
+
+func TestEffectivePrice(t *testing.T) {
+	changes := []model.PriceChange{
+		{DishID: "ramen", Price: 800, EffectiveAt: at(1, 0, 0)},
+		{DishID: "ramen", Price: 900, EffectiveAt: at(10, 0, 0)},
+		{DishID: "ramen", Price: 1200, EffectiveAt: at(20, 0, 0)},
+		{DishID: "gyoza", Price: 100, EffectiveAt: at(1, 0, 0)},
+	}
+	happyHour := model.PriceRule{
+		ID: "happy-hour", Category: "main", StartTime: "17:00", EndTime: "19:00",
+		AdjustmentType: model.AdjustmentPercent, Value: 10, Active: true,
+	}
+
+	tests := []struct {
+		name    string
+		dishID  string
+		stored  int
+		changes []model.PriceChange
+		rules   []model.PriceRule
+		at      time.Time
+		want    Resolution
+	}{
+		{
+			name:   "stored price without changes or rules",
+			dishID: "ramen", stored: 1000, at: at(16, 12, 0),
+			want: Resolution{Price: 1000, BasePrice: 1000},
+		},
+		{
+			name:   "latest change before at",
+			dishID: "ramen", stored: 1000, changes: changes, at: at(16, 12, 0),
+			want: Resolution{Price: 900, BasePrice: 900},
+		},
+		{
+			name:   "change applies from its effective time",
+			dishID: "ramen", stored: 1000, changes: changes, at: at(20, 0, 0),
+			want: Resolution{Price: 1200, BasePrice: 1200},
+		},
+		{
+			name:   "changes of other dishes are ignored",
+			dishID: "udon", stored: 700, changes: changes, at: at(16, 12, 0),
+			want: Resolution{Price: 700, BasePrice: 700},
+		},
+		{
+			name:   "percent rule on the changed price",
+			dishID: "ramen", stored: 1000, changes: changes, rules: []model.PriceRule{happyHour}, at: at(16, 17, 30),
+			want: Resolution{Price: 810, BasePrice: 900, RuleID: "happy-hour"},
+		},
+		{
+			name:   "percent discount below one yen is truncated",
+			dishID: "ramen", stored: 999, rules: []model.PriceRule{happyHour}, at: at(16, 17, 30),
+			want: Resolution{Price: 900, BasePrice: 999, RuleID: "happy-hour"},
+		},
+		{
+			name:   "end time is exclusive",
+			dishID: "ramen", stored: 1000, rules: []model.PriceRule{happyHour}, at: at(16, 19, 0),
+			want: Resolution{Price: 1000, BasePrice: 1000},
+		},
+		{
+			name:   "cheapest rule wins",
+			dishID: "ramen", stored: 1000, at: at(16, 18, 0),
+			rules: []model.PriceRule{
+				happyHour,
+				{ID: "amount", DishID: "ramen", StartTime: "00:00", EndTime: "23:59", AdjustmentType: model.AdjustmentAmount, Value: 150, Active: true},
+				{ID: "fixed", DishID: "ramen", StartTime: "00:00", EndTime: "23:59", AdjustmentType: model.AdjustmentFixed, Value: 880, Active: true},
+			},
+			want: Resolution{Price: 850, BasePrice: 1000, RuleID: "amount"},
+		},
+		{
+			name:   "rules for other dishes and categories are ignored",
+			dishID: "ramen", stored: 1000, at: at(16, 18, 0),
+			rules: []model.PriceRule{
+				{ID: "other-dish", DishID: "udon", StartTime: "00:00", EndTime: "23:59", AdjustmentType: model.AdjustmentFixed, Value: 1, Active: true},
+				{ID: "other-category", Category: "drink", StartTime: "00:00", EndTime: "23:59", AdjustmentType: model.AdjustmentFixed, Value: 1, Active: true},
+			},
+			want: Resolution{Price: 1000, BasePrice: 1000},
+		},
+		{
+			name:   "inactive rule is ignored",
+			dishID: "ramen", stored: 1000, at: at(16, 18, 0),
+			rules: []model.PriceRule{
+				{ID: "inactive", DishID: "ramen", StartTime: "00:00", EndTime: "23:59", AdjustmentType: model.AdjustmentFixed, Value: 1},
+			},
+			want: Resolution{Price: 1000, BasePrice: 1000},
+		},
+		{
+			name:   "price does not go below zero",
+			dishID: "ramen", stored: 1000, at: at(16, 18, 0),
+			rules: []model.PriceRule{
+				{ID: "free", DishID: "ramen", StartTime: "00:00", EndTime: "23:59", AdjustmentType: model.AdjustmentAmount, Value: 5000, Active: true},
+			},
+			want: Resolution{Price: 0, BasePrice: 1000, RuleID: "free"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EffectivePrice(tt.dishID, "main", tt.stored, tt.changes, tt.rules, tt.at, jst)
+			if got != tt.want {
+				t.Errorf("EffectivePrice = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleActiveAt(t *testing.T) {
+	friday := int(time.Friday)
+	lateNight := model.PriceRule{StartTime: "22:00", EndTime: "02:00", DaysOfWeek: []int{friday}, Active: true}
+
+	tests := []struct {
+		name string
+		rule model.PriceRule
+		at   time.Time
+		want bool
+	}{
+		{name: "overnight rule on its day", rule: lateNight, at: at(16, 23, 0), want: true},
+		{name: "overnight rule after midnight uses the start day", rule: lateNight, at: at(17, 1, 30), want: true},
+		{name: "overnight rule ends at end time", rule: lateNight, at: at(17, 2, 0), want: false},
+		{name: "overnight rule on the next day evening", rule: lateNight, at: at(17, 23, 0), want: false},
+		{name: "overnight rule before midnight of the start day", rule: lateNight, at: at(16, 1, 0), want: false},
+		{
+			name: "overnight rule after midnight on its end date",
+			rule: model.PriceRule{StartTime: "22:00", EndTime: "02:00", EndsOn: "2026-10-16", Active: true},
+			at:   at(17, 1, 0), want: true,
+		},
+		{
+			name: "before starts on",
+			rule: model.PriceRule{StartTime: "00:00", EndTime: "23:59", StartsOn: "2026-10-17", Active: true},
+			at:   at(16, 12, 0), want: false,
+		},
+		{
+			name: "after ends on",
+			rule: model.PriceRule{StartTime: "00:00", EndTime: "23:59", EndsOn: "2026-10-15", Active: true},
+			at:   at(16, 12, 0), want: false,
+		},
+		{
+			name: "time is judged in the rule time zone",
+			rule: model.PriceRule{StartTime: "17:00", EndTime: "19:00", Active: true},
+			at:   time.Date(2026, time.October, 16, 8, 30, 0, 0, time.UTC), want: true,
+		},
+		{
+			name: "inactive rule",
+			rule: model.PriceRule{StartTime: "00:00", EndTime: "23:59"},
+			at:   at(16, 12, 0), want: false,
+		},
+		{
+			name: "invalid time",
+			rule: model.PriceRule{StartTime: "25:00", EndTime: "23:59", Active: true},
+			at:   at(16, 12, 0), want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RuleActiveAt(tt.rule, tt.at, jst); got != tt.want {
+				t.Errorf("RuleActiveAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver(t *testing.T) {
+	resolver := NewResolver(
+		[]model.PriceChange{
+			{DishID: "ramen", Price: 900, EffectiveAt: at(10, 0, 0)},
+			{DishID: "gyoza", Price: 400, EffectiveAt: at(10, 0, 0)},
+		},
+		[]model.PriceRule{
+			{ID: "side-sale", Category: "side", StartTime: "11:00", EndTime: "14:00", AdjustmentType: model.AdjustmentAmount, Value: 100, Active: true},
+		},
+		jst,
+	)
+
+	tests := []struct {
+		dishID, category string
+		stored           int
+		at               time.Time
+		want             Resolution
+	}{
+		{"ramen", "main", 1000, at(16, 12, 0), Resolution{Price: 900, BasePrice: 900}},
+		{"ramen", "main", 1000, at(9, 12, 0), Resolution{Price: 1000, BasePrice: 1000}},
+		{"gyoza", "side", 350, at(16, 12, 0), Resolution{Price: 300, BasePrice: 400, RuleID: "side-sale"}},
+		{"gyoza", "side", 350, at(16, 15, 0), Resolution{Price: 400, BasePrice: 400}},
+		{"rice", "side", 200, at(16, 12, 0), Resolution{Price: 100, BasePrice: 200, RuleID: "side-sale"}},
+	}
+
+	for _, tt := range tests {
+		if got := resolver.Resolve(tt.dishID, tt.category, tt.stored, tt.at); got != tt.want {
+			t.Errorf("Resolve(%s, %s, %d, %v) = %+v, want %+v", tt.dishID, tt.category, tt.stored, tt.at, got, tt.want)
+		}
+	}
+}
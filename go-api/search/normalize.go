@@ -0,0 +1,106 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize 検索用に文字列を正規化する
+//
+// 全角・半角の違い（NFKC）、大文字・小文字、カタカナ・ひらがなの違いをなくし、
+// 連続する空白を1つにまとめる。DBの search_text も同じ規則で作成する。
+func Normalize(s string) string {
+	s = strings.ToLower(norm.NFKC.String(s))
+	s = strings.Map(katakanaToHiragana, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Document 料理の検索対象文字列（search_text）を作成する
+func Document(nameJa, nameEn, reading string) string {
+	var parts []string
+	for _, s := range []string{nameJa, reading, nameEn} {
+		if n := Normalize(s); n != "" {
+			parts = append(parts, n)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Variants 検索語の正規化結果と、ローマ字入力をひらがなに変換したものを返す
+func Variants(word string) []string {
+	normalized := Normalize(word)
+	if normalized == "" {
+		return nil
+	}
+
+	variants := []string{normalized}
+	if kana, ok := RomajiToHiragana(normalized); ok && kana != normalized {
+		variants = append(variants, kana)
+	}
+	return variants
+}
+
+// IsKana ふりがなとして使える文字（ひらがな・カタカナ・長音記号・空白）のみかどうか
+func IsKana(s string) bool {
+	for _, r := range norm.NFKC.String(s) {
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+		case r == 'ー', r == '・', unicode.IsSpace(r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// katakanaToHiragana カタカナ（ァ〜ヶ、ヽヾ）を対応するひらがなに変換する
+func katakanaToHiragana(r rune) rune {
+	switch {
+	case r >= 'ァ' && r <= 'ヶ':
+		return r - ('ァ' - 'ぁ')
+	case r == 'ヽ' || r == 'ヾ':
+		return r - ('ァ' - 'ぁ')
+	default:
+		return r
+	}
+}
+
+// SQLNormalize Normalize と同じ正規化（空白の整理を除く）を行う PostgreSQL の式を返す
+func SQLNormalize(expr string) string {
+	return "translate(lower(normalize(" + expr + ", NFKC)), '" + sqlKatakana + "', '" + sqlHiragana + "')"
+}
+
+// sqlKatakana, sqlHiragana SQLNormalize で translate に渡す変換前後の文字
+var sqlKatakana, sqlHiragana = kanaTable()
+
+// kanaTable katakanaToHiragana が変換するカタカナと対応するひらがなの一覧を作成する
+func kanaTable() (string, string) {
+	var kata, hira strings.Builder
+	for r := 'ァ'; r <= 'ヾ'; r++ {
+		if h := katakanaToHiragana(r); h != r {
+			kata.WriteRune(r)
+			hira.WriteRune(h)
+		}
+	}
+	return kata.String(), hira.String()
+}
+
+// EditTolerance 語の長さに応じて許容する編集距離（タイプミスの文字数）を返す
+// 英数字以外を含む語や短い語は誤りの判定が難しいため0とする
+func EditTolerance(word string) int {
+	for _, r := range word {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return 0
+		}
+	}
+	switch n := len(word); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"ラーメン", "らーめん"},
+		{"ｶﾞｯﾂﾘ定食", "がっつり定食"},
+		{"ＲＡＭＥＮ　 Ｓｅｔ", "ramen set"},
+		{"ヴァニラ", "ゔぁにら"},
+		{"  醤油\tラーメン  ", "醤油 らーめん"},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVariants(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Ramen", []string{"ramen", "らめん"}},
+		{"ＧＹＯＵＺＡ", []string{"gyouza", "ぎょうざ"}},
+		{"ラーメン", []string{"らーめん"}},
+		{"beer", []string{"beer"}},
+		{" ", nil},
+	}
+	for _, tt := range tests {
+		if got := Variants(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Variants(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsKana(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"らーめん", true},
+		{"ラーメン・セット", true},
+		{"ﾗｰﾒﾝ", true},
+		{"", true},
+		{"ramen", false},
+		{"拉麺", false},
+	}
+	for _, tt := range tests {
+		if got := IsKana(tt.in); got != tt.want {
+			t.Errorf("IsKana(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEditTolerance(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"ume", 0},
+		{"ramen", 1},
+		{"tonkatsu", 2},
+		{"udon-2", 0},
+		{"らーめんせっと", 0},
+	}
+	for _, tt := range tests {
+		if got := EditTolerance(tt.in); got != tt.want {
+			t.Errorf("EditTolerance(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
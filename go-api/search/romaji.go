@@ -0,0 +1,107 @@
+package search
+
+import "strings"
+
+// romajiTable ローマ字（ヘボン式・訓令式）からひらがなへの変換表
+var romajiTable = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"sa": "さ", "si": "し", "shi": "し", "su": "す", "se": "せ", "so": "そ",
+	"ta": "た", "ti": "ち", "chi": "ち", "tu": "つ", "tsu": "つ", "te": "て", "to": "と",
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"ha": "は", "hi": "ひ", "hu": "ふ", "fu": "ふ", "he": "へ", "ho": "ほ",
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"wa": "わ", "wo": "を",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"za": "ざ", "zi": "じ", "ji": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"da": "だ", "di": "ぢ", "du": "づ", "de": "で", "do": "ど",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"sha": "しゃ", "shu": "しゅ", "she": "しぇ", "sho": "しょ",
+	"sya": "しゃ", "syu": "しゅ", "syo": "しょ",
+	"cha": "ちゃ", "chu": "ちゅ", "che": "ちぇ", "cho": "ちょ",
+	"tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+	"ja": "じゃ", "ju": "じゅ", "je": "じぇ", "jo": "じょ",
+	"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"thi": "てぃ", "dhi": "でぃ",
+	"va": "ゔぁ", "vi": "ゔぃ", "vu": "ゔ", "ve": "ゔぇ", "vo": "ゔぉ",
+	"nn": "ん", "n'": "ん",
+}
+
+// RomajiToHiragana ローマ字をひらがなに変換する
+// 変換できない英字が残った場合（英単語など）は ok が false になる
+func RomajiToHiragana(s string) (kana string, ok bool) {
+	var b strings.Builder
+	ok = true
+	hasLatin := false
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < 'a' || c > 'z' {
+			if c == '-' && hasLatin {
+				b.WriteString("ー")
+				i++
+				continue
+			}
+			// ひらがな・数字などはそのまま残す
+			end := i + 1
+			for end < len(s) && (s[end] < 'a' || s[end] > 'z') && s[end] != '-' {
+				end++
+			}
+			b.WriteString(s[i:end])
+			i = end
+			continue
+		}
+		hasLatin = true
+
+		// 促音（子音の重複。nn は「ん」、tch は「っち」）
+		if i+1 < len(s) && (s[i+1] == c && c != 'n' && !isRomajiVowel(c) || c == 't' && strings.HasPrefix(s[i+1:], "ch")) {
+			b.WriteString("っ")
+			i++
+			continue
+		}
+
+		// 撥音（n の後に母音・y が続かない場合）
+		if c == 'n' && (i+1 == len(s) || !isRomajiVowel(s[i+1]) && s[i+1] != 'y' && s[i+1] != 'n' && s[i+1] != '\'') {
+			b.WriteString("ん")
+			i++
+			continue
+		}
+
+		matched := false
+		for l := 3; l >= 1; l-- {
+			if i+l > len(s) {
+				continue
+			}
+			if kana, found := romajiTable[s[i:i+l]]; found {
+				b.WriteString(kana)
+				i += l
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(c)
+			ok = false
+			i++
+		}
+	}
+
+	return b.String(), ok && hasLatin
+}
+
+// isRomajiVowel ローマ字の母音かどうか
+func isRomajiVowel(c byte) bool {
+	return c == 'a' || c == 'i' || c == 'u' || c == 'e' || c == 'o'
+}
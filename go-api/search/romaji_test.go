@@ -0,0 +1,30 @@
+package search
+
+import "testing"
+
+func TestRomajiToHiragana(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"ramen", "らめん", true},
+		{"tonkatsu", "とんかつ", true},
+		{"gyouza", "ぎょうざ", true},
+		{"matcha", "まっちゃ", true},
+		{"kappa", "かっぱ", true},
+		{"shin'ya", "しんや", true},
+		{"tyahan", "ちゃはん", true},
+		{"kare-", "かれー", true},
+		{"ramen 2", "らめん 2", true},
+		{"beer", "べえr", false},
+		{"123", "123", false},
+		{"らーめん", "らーめん", false},
+	}
+	for _, tt := range tests {
+		got, ok := RomajiToHiragana(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("RomajiToHiragana(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
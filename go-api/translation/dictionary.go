@@ -0,0 +1,91 @@
+package translation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/smilemasa/go-api/i18n"
+	"github.com/smilemasa/go-api/search"
+)
+
+// Dictionary 用語集を使ってオフラインで翻訳する
+//
+// 料理名を用語集の用語で最長一致に区切り、訳語を順に並べる（例: チキン + カレー → chicken curry）。
+// 区切れない文字が残った場合は ErrNoTranslation を返す。
+type Dictionary struct {
+	glossary Glossary
+}
+
+// NewDictionary 用語集を使う翻訳を作成する
+func NewDictionary(glossary Glossary) *Dictionary {
+	return &Dictionary{glossary: glossary}
+}
+
+// Name 翻訳バックエンドの名前
+func (d *Dictionary) Name() string {
+	return DefaultBackend
+}
+
+// Translate 用語集で text を target ロケールに翻訳する（source は日本語のみ対応）
+func (d *Dictionary) Translate(ctx context.Context, text, source, target string) (string, error) {
+	if source != i18n.Japanese {
+		return "", ErrNoTranslation
+	}
+
+	terms, err := d.glossary.Terms(ctx, target)
+	if err != nil {
+		return "", err
+	}
+
+	// 用語・料理名ともに検索と同じ規則で正規化して照合する（カタカナ・ひらがな、全角・半角の違いをなくす）
+	dict := make(map[string]string, len(terms))
+	longest := 0
+	for term, translated := range terms {
+		key := []rune(search.Normalize(term))
+		if len(key) == 0 {
+			continue
+		}
+		dict[string(key)] = translated
+		longest = max(longest, len(key))
+	}
+
+	runes := []rune(search.Normalize(text))
+	var words []string
+	for i := 0; i < len(runes); {
+		matched := 0
+		for l := min(longest, len(runes)-i); l > 0; l-- {
+			if translated, ok := dict[string(runes[i:i+l])]; ok {
+				words = append(words, translated)
+				matched = l
+				break
+			}
+		}
+		switch {
+		case matched > 0:
+			i += matched
+		case isSeparator(runes[i]):
+			i++
+		default:
+			return "", ErrNoTranslation
+		}
+	}
+	if len(words) == 0 {
+		return "", ErrNoTranslation
+	}
+
+	return strings.Join(words, wordSeparator(target)), nil
+}
+
+// isSeparator 用語に一致しない場合に読み飛ばしてよい区切りの文字（空白・記号、「の」など）かどうか
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || r == '・' || r == 'の'
+}
+
+// wordSeparator 訳語の間に入れる文字（中国語は空白で区切らない）
+func wordSeparator(locale string) string {
+	if locale == i18n.SimplifiedChinese || locale == i18n.TraditionalChinese {
+		return ""
+	}
+	return " "
+}
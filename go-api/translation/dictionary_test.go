@@ -0,0 +1,83 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/smilemasa/go-api/i18n"
+)
+
+// mapGlossary ロケールごとの用語集（テスト用）
+type mapGlossary map[string]map[string]string
+
+func (g mapGlossary) Terms(_ context.Context, target string) (map[string]string, error) {
+	return g[target], nil
+}
+
+// errGlossary 用語集の取得に失敗する（テスト用）
+type errGlossary struct{ err error }
+
+func (g errGlossary) Terms(context.Context, string) (map[string]string, error) {
+	return nil, g.err
+}
+
+func TestDictionaryTranslate(t *testing.T) {
+	glossary := mapGlossary{
+		i18n.English: {
+			"チキン":   "chicken",
+			"チキンカツ": "chicken cutlet",
+			"カレー":   "curry",
+			"醤油":    "soy sauce",
+			"ラーメン":  "ramen",
+		},
+		i18n.SimplifiedChinese: {
+			"チキン": "鸡肉",
+			"カレー": "咖喱",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		source  string
+		target  string
+		want    string
+		wantErr error
+	}{
+		{name: "terms in order", text: "チキンカレー", target: i18n.English, want: "chicken curry"},
+		{name: "longest match", text: "チキンカツカレー", target: i18n.English, want: "chicken cutlet curry"},
+		{name: "normalized like search", text: "ちきん　ｶﾚｰ", target: i18n.English, want: "chicken curry"},
+		{name: "separators are skipped", text: "醤油の・ラーメン", target: i18n.English, want: "soy sauce ramen"},
+		{name: "chinese without spaces", text: "チキンカレー", target: i18n.SimplifiedChinese, want: "鸡肉咖喱"},
+		{name: "unknown word", text: "チキン南蛮", target: i18n.English, wantErr: ErrNoTranslation},
+		{name: "separators only", text: "・ ", target: i18n.English, wantErr: ErrNoTranslation},
+		{name: "no glossary for target", text: "チキンカレー", target: i18n.Korean, wantErr: ErrNoTranslation},
+		{name: "source other than japanese", text: "chicken curry", source: i18n.English, target: i18n.Korean, wantErr: ErrNoTranslation},
+	}
+
+	d := NewDictionary(glossary)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			if source == "" {
+				source = i18n.Japanese
+			}
+			got, err := d.Translate(context.Background(), tt.text, source, tt.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Translate(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Translate(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDictionaryGlossaryError(t *testing.T) {
+	glossaryErr := errors.New("glossary unavailable")
+	_, err := NewDictionary(errGlossary{glossaryErr}).Translate(context.Background(), "カレー", i18n.Japanese, i18n.English)
+	if !errors.Is(err, glossaryErr) {
+		t.Errorf("Translate error = %v, want %v", err, glossaryErr)
+	}
+}
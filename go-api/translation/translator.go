@@ -0,0 +1,64 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNoTranslation 翻訳できなかった場合のエラー（用語集に訳語がないなど）
+var ErrNoTranslation = errors.New("translation: no translation available")
+
+// Translator 料理名を他の言語に翻訳する
+type Translator interface {
+	// Name 翻訳バックエンドの名前（下書きの作成元として記録する）
+	Name() string
+	// Translate text を source ロケールから target ロケールに翻訳する
+	Translate(ctx context.Context, text, source, target string) (string, error)
+}
+
+// Glossary 用語集（日本語の用語と各ロケールの訳語）
+type Glossary interface {
+	// Terms target ロケールの訳語を日本語の用語をキーにして返す
+	Terms(ctx context.Context, target string) (map[string]string, error)
+}
+
+// Factory 翻訳バックエンドを作成する関数
+type Factory func(glossary Glossary) (Translator, error)
+
+// DefaultBackend 設定がない場合に使う翻訳バックエンド
+const DefaultBackend = "dictionary"
+
+var backends = map[string]Factory{
+	DefaultBackend: func(glossary Glossary) (Translator, error) {
+		return NewDictionary(glossary), nil
+	},
+}
+
+// Register 翻訳バックエンドを登録する（外部の翻訳サービスを追加する場合に init から呼ぶ）
+func Register(name string, factory Factory) {
+	if _, exists := backends[name]; exists {
+		panic("translation: backend already registered: " + name)
+	}
+	backends[name] = factory
+}
+
+// Backends 登録済みの翻訳バックエンドの名前を返す
+func Backends() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New 指定した翻訳バックエンドを作成する
+func New(name string, glossary Glossary) (Translator, error) {
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("translation: unknown backend %q", name)
+	}
+	return factory(glossary)
+}
@@ -3,18 +3,30 @@ package utils
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"github.com/smilemasa/go-api/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/iterator"
 )
 
 // GCSClient Google Cloud Storage クライアント
 type GCSClient struct {
 	client     *storage.Client
 	bucketName string
+	urlOptions SignedURLOptions
+	urlCache   *signedURLCache
 }
 
+var tracer = otel.Tracer("github.com/smilemasa/go-api/utils")
+
 var (
 	gcsInstance *GCSClient
 	gcsOnce     sync.Once
@@ -22,7 +34,8 @@ var (
 )
 
 // InitGCSClient GCSクライアントを初期化（一度だけ実行される）
-func InitGCSClient(ctx context.Context, bucketName string) error {
+// urlOptions はダウンロード用の署名付きURLの有効期限とキャッシュの設定
+func InitGCSClient(ctx context.Context, bucketName string, urlOptions SignedURLOptions) error {
 	gcsOnce.Do(func() {
 		client, err := storage.NewClient(ctx)
 		if err != nil {
@@ -33,6 +46,8 @@ func InitGCSClient(ctx context.Context, bucketName string) error {
 		gcsInstance = &GCSClient{
 			client:     client,
 			bucketName: bucketName,
+			urlOptions: urlOptions,
+			urlCache:   newSignedURLCache(urlOptions.CacheSize),
 		}
 	})
 	return gcsErr
@@ -54,14 +69,37 @@ func Close() error {
 	return nil
 }
 
+// startOperation ストレージ操作のスパンを開始し、終了時にスパンと処理時間を記録する関数を返す
+func startOperation(ctx context.Context, operation, objectName string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "gcs "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("gcs.object", objectName)),
+	)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.ObserveStorage(operation, start, err)
+	}
+}
+
 // CreateSignedURL ファイルアップロード用のSignedURLを作成
-func (g *GCSClient) CreateSignedURL(ctx context.Context, objectName string, expiration time.Duration) (string, error) {
+// Content-Type とサイズは署名に含まれるため、アップロード時は同じ Content-Type と
+// x-goog-content-length-range ヘッダー（UploadSizeHeader）を送る必要がある（size バイト以外のファイルはGCSが拒否する）
+func (g *GCSClient) CreateSignedURL(ctx context.Context, objectName, contentType string, size int64, expiration time.Duration) (_ string, err error) {
+	ctx, end := startOperation(ctx, "sign_upload", objectName)
+	defer func() { end(err) }()
+
 	// Pre-signed URLの設定
 	opts := &storage.SignedURLOptions{
-		Scheme: storage.SigningSchemeV4,
-		Method: "PUT",
+		Scheme:      storage.SigningSchemeV4,
+		Method:      "PUT",
+		ContentType: contentType,
 		Headers: []string{
-			"Content-Type",
+			UploadSizeHeader + ":" + UploadSizeRange(size),
 		},
 		Expires: time.Now().Add(expiration),
 	}
@@ -75,8 +113,19 @@ func (g *GCSClient) CreateSignedURL(ctx context.Context, objectName string, expi
 	return url, nil
 }
 
+// UploadSizeHeader 署名付きURLでのアップロード時にサイズの上限をGCSに確認させるヘッダー
+const UploadSizeHeader = "x-goog-content-length-range"
+
+// UploadSizeRange UploadSizeHeader に指定する値（size バイトちょうど）
+func UploadSizeRange(size int64) string {
+	return fmt.Sprintf("%d,%d", size, size)
+}
+
 // CreateDownloadSignedURL ファイルダウンロード用のSignedURLを作成
-func (g *GCSClient) CreateDownloadSignedURL(ctx context.Context, objectName string, expiration time.Duration) (string, error) {
+func (g *GCSClient) CreateDownloadSignedURL(ctx context.Context, objectName string, expiration time.Duration) (_ string, err error) {
+	ctx, end := startOperation(ctx, "sign_download", objectName)
+	defer func() { end(err) }()
+
 	// Pre-signed URLの設定（ダウンロード用）
 	opts := &storage.SignedURLOptions{
 		Scheme:  storage.SigningSchemeV4,
@@ -94,7 +143,10 @@ func (g *GCSClient) CreateDownloadSignedURL(ctx context.Context, objectName stri
 }
 
 // UploadFile ファイルをGoogle Cloud Storageにアップロード
-func (g *GCSClient) UploadFile(ctx context.Context, objectName string, data []byte, contentType string) error {
+func (g *GCSClient) UploadFile(ctx context.Context, objectName string, data []byte, contentType string) (err error) {
+	ctx, end := startOperation(ctx, "upload", objectName)
+	defer func() { end(err) }()
+
 	wc := g.client.Bucket(g.bucketName).Object(objectName).NewWriter(ctx)
 	wc.ContentType = contentType
 
@@ -107,8 +159,90 @@ func (g *GCSClient) UploadFile(ctx context.Context, objectName string, data []by
 	return nil
 }
 
+// ImmutableCacheControl 内容が変わらないファイル（内容のハッシュをオブジェクト名に含むファイル）のCache-Control
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+// UploadImmutableFile 内容が変わらないファイルを ImmutableCacheControl 付きでアップロード
+// 同じ名前のファイルがすでにある場合は同じ内容とみなしてアップロードしない
+func (g *GCSClient) UploadImmutableFile(ctx context.Context, objectName string, data []byte, contentType string) (err error) {
+	ctx, end := startOperation(ctx, "upload", objectName)
+	defer func() { end(err) }()
+
+	exists, err := g.FileExists(ctx, objectName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	wc := g.client.Bucket(g.bucketName).Object(objectName).NewWriter(ctx)
+	wc.ContentType = contentType
+	wc.CacheControl = ImmutableCacheControl
+
+	if _, err := wc.Write(data); err != nil {
+		wc.Close()
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+
+	return nil
+}
+
+// CopyImmutableFile 保存済みのファイルを、内容が変わらないファイルとしてキャッシュ期間を最長にして別名でコピーする
+// コピー先が既に存在する場合は同じ内容のためコピーしない
+func (g *GCSClient) CopyImmutableFile(ctx context.Context, srcName, dstName, contentType string) (err error) {
+	ctx, end := startOperation(ctx, "copy", dstName)
+	defer func() { end(err) }()
+
+	exists, err := g.FileExists(ctx, dstName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	bucket := g.client.Bucket(g.bucketName)
+	copier := bucket.Object(dstName).CopierFrom(bucket.Object(srcName))
+	copier.ContentType = contentType
+	copier.CacheControl = ImmutableCacheControl
+	if _, err := copier.Run(ctx); err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	return nil
+}
+
+// OpenFile ファイルを読み込み用に開く（呼び出し側で Close する）
+// ファイルが存在しない場合は storage.ErrObjectNotExist をラップしたエラーを返す
+func (g *GCSClient) OpenFile(ctx context.Context, objectName string) (_ *storage.Reader, err error) {
+	ctx, end := startOperation(ctx, "open", objectName)
+	defer func() { end(err) }()
+
+	rc, err := g.client.Bucket(g.bucketName).Object(objectName).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	return rc, nil
+}
+
+// ObjectName DBに保存された画像パスからGCSのオブジェクト名を取得する
+// 完全URLで保存されている古いデータの場合はファイル名部分を使う
+func ObjectName(img string) string {
+	if strings.HasPrefix(img, "https://storage.googleapis.com/") {
+		urlParts := strings.Split(img, "/")
+		return urlParts[len(urlParts)-1]
+	}
+	return img
+}
+
 // DeleteFile Google Cloud Storageからファイルを削除
-func (g *GCSClient) DeleteFile(ctx context.Context, objectName string) error {
+func (g *GCSClient) DeleteFile(ctx context.Context, objectName string) (err error) {
+	ctx, end := startOperation(ctx, "delete", objectName)
+	defer func() { end(err) }()
+
 	obj := g.client.Bucket(g.bucketName).Object(objectName)
 	if err := obj.Delete(ctx); err != nil {
 		return fmt.Errorf("failed to delete object: %w", err)
@@ -117,9 +251,26 @@ func (g *GCSClient) DeleteFile(ctx context.Context, objectName string) error {
 	return nil
 }
 
+// Ping バケットにアクセスできるか確認する（ヘルスチェック用）
+// オブジェクトの一覧を1件だけ取得する
+func (g *GCSClient) Ping(ctx context.Context) (err error) {
+	ctx, end := startOperation(ctx, "ping", "")
+	defer func() { end(err) }()
+
+	it := g.client.Bucket(g.bucketName).Objects(ctx, &storage.Query{})
+	it.PageInfo().MaxSize = 1
+	if _, listErr := it.Next(); listErr != nil && listErr != iterator.Done {
+		return fmt.Errorf("failed to list objects: %w", listErr)
+	}
+	return nil
+}
+
 // FileExists ファイルが存在するかチェック
-func (g *GCSClient) FileExists(ctx context.Context, objectName string) (bool, error) {
-	_, err := g.client.Bucket(g.bucketName).Object(objectName).Attrs(ctx)
+func (g *GCSClient) FileExists(ctx context.Context, objectName string) (_ bool, err error) {
+	ctx, end := startOperation(ctx, "exists", objectName)
+	defer func() { end(err) }()
+
+	_, err = g.client.Bucket(g.bucketName).Object(objectName).Attrs(ctx)
 	if err != nil {
 		if err == storage.ErrObjectNotExist {
 			return false, nil
@@ -128,3 +279,53 @@ func (g *GCSClient) FileExists(ctx context.Context, objectName string) (bool, er
 	}
 	return true, nil
 }
+
+// ReadFile Google Cloud Storageからファイルを読み込む
+func (g *GCSClient) ReadFile(ctx context.Context, objectName string) (_ []byte, err error) {
+	ctx, end := startOperation(ctx, "read", objectName)
+	defer func() { end(err) }()
+
+	rc, err := g.client.Bucket(g.bucketName).Object(objectName).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+
+	return data, nil
+}
+
+// FileAttrs ファイルのContent-Typeとサイズを取得
+func (g *GCSClient) FileAttrs(ctx context.Context, objectName string) (_ string, _ int64, err error) {
+	ctx, end := startOperation(ctx, "attrs", objectName)
+	defer func() { end(err) }()
+
+	attrs, err := g.client.Bucket(g.bucketName).Object(objectName).Attrs(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get object attributes: %w", err)
+	}
+	return attrs.ContentType, attrs.Size, nil
+}
+
+// ReadFileHead ファイルの先頭 n バイトを読み込む（ファイル形式の判定用）
+func (g *GCSClient) ReadFileHead(ctx context.Context, objectName string, n int64) (_ []byte, err error) {
+	ctx, end := startOperation(ctx, "read_head", objectName)
+	defer func() { end(err) }()
+
+	rc, err := g.client.Bucket(g.bucketName).Object(objectName).NewRangeReader(ctx, 0, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smilemasa/go-api/metrics"
+	"golang.org/x/sync/errgroup"
+)
+
+// SignedURLOptions ダウンロード用の署名付きURLの発行とキャッシュの設定
+type SignedURLOptions struct {
+	// TTL 発行する署名付きURLの有効期限
+	TTL time.Duration
+	// MinRemaining キャッシュしたURLを再利用する最低残り有効期間（これを下回ると再署名する）
+	MinRemaining time.Duration
+	// CacheSize キャッシュするURLの最大件数（0の場合はキャッシュしない）
+	CacheSize int
+	// Concurrency 一括署名で同時に署名する件数
+	Concurrency int
+}
+
+// 署名付きURLキャッシュのメトリクス（ヒット率は hit / (hit + miss) で算出する）
+var (
+	signedURLHits   = metrics.SignedURLCacheRequests.WithLabelValues("hit")
+	signedURLMisses = metrics.SignedURLCacheRequests.WithLabelValues("miss")
+)
+
+// signedURLCache オブジェクト名ごとにダウンロード用の署名付きURLを保持するキャッシュ
+type signedURLCache struct {
+	mu      sync.Mutex
+	entries map[string]signedURLEntry
+	size    int
+}
+
+type signedURLEntry struct {
+	url       string
+	expiresAt time.Time
+}
+
+func newSignedURLCache(size int) *signedURLCache {
+	return &signedURLCache{entries: make(map[string]signedURLEntry), size: size}
+}
+
+// get 残り有効期間が minRemaining 以上のURLを返す
+func (c *signedURLCache) get(objectName string, minRemaining time.Duration) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[objectName]
+	if !ok || time.Until(entry.expiresAt) < minRemaining {
+		return "", false
+	}
+	return entry.url, true
+}
+
+// put URLを保存する。上限に達した場合は期限切れのURLを、それでも足りなければ任意のURLを削除する
+func (c *signedURLCache) put(objectName, url string, expiresAt time.Time) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[objectName]; !ok && len(c.entries) >= c.size {
+		now := time.Now()
+		for name, entry := range c.entries {
+			if entry.expiresAt.Before(now) {
+				delete(c.entries, name)
+				metrics.SignedURLCacheEvictions.Inc()
+			}
+		}
+		for name := range c.entries {
+			if len(c.entries) < c.size {
+				break
+			}
+			delete(c.entries, name)
+			metrics.SignedURLCacheEvictions.Inc()
+		}
+	}
+	c.entries[objectName] = signedURLEntry{url: url, expiresAt: expiresAt}
+}
+
+// SignedDownloadURL ダウンロード用の署名付きURLを取得する
+// 残り有効期間が十分なURLがキャッシュにあれば再利用し、なければ TTL の有効期限で署名する
+func (g *GCSClient) SignedDownloadURL(ctx context.Context, objectName string) (string, error) {
+	if url, ok := g.urlCache.get(objectName, g.urlOptions.MinRemaining); ok {
+		signedURLHits.Inc()
+		return url, nil
+	}
+	signedURLMisses.Inc()
+
+	expiresAt := time.Now().Add(g.urlOptions.TTL)
+	url, err := g.CreateDownloadSignedURL(ctx, objectName, g.urlOptions.TTL)
+	if err != nil {
+		return "", err
+	}
+	g.urlCache.put(objectName, url, expiresAt)
+	return url, nil
+}
+
+// SignedDownloadURLs 複数のオブジェクトの署名付きURLをまとめて取得し、オブジェクト名をキーにして返す
+// キャッシュにないURLは Concurrency 件ずつ並行して署名する
+func (g *GCSClient) SignedDownloadURLs(ctx context.Context, objectNames []string) (map[string]string, error) {
+	urls := make(map[string]string, len(objectNames))
+	var missing []string
+	for _, objectName := range objectNames {
+		if _, ok := urls[objectName]; ok || objectName == "" {
+			continue
+		}
+		if url, ok := g.urlCache.get(objectName, g.urlOptions.MinRemaining); ok {
+			signedURLHits.Inc()
+			urls[objectName] = url
+			continue
+		}
+		// 重複したオブジェクト名を一度だけ署名するため仮に登録する
+		urls[objectName] = ""
+		missing = append(missing, objectName)
+	}
+
+	signed := make([]string, len(missing))
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(max(g.urlOptions.Concurrency, 1))
+	for i, objectName := range missing {
+		group.Go(func() error {
+			url, err := g.SignedDownloadURL(ctx, objectName)
+			signed[i] = url
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	for i, objectName := range missing {
+		urls[objectName] = signed[i]
+	}
+	return urls, nil
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/config"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// StartDishPurger ゴミ箱の料理を定期的に完全削除するワーカーを起動する
+// ctx がキャンセルされると停止し、返り値のチャネルが閉じられる
+func StartDishPurger(ctx context.Context, retention, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purgeDeletedDishes(ctx, retention)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return done
+}
+
+// purgeDeletedDishes 1回分の完全削除を実行する
+func purgeDeletedDishes(ctx context.Context, retention time.Duration) {
+	count, err := PurgeDeletedDishes(ctx, retention)
+	if err != nil {
+		slog.ErrorContext(ctx, "ゴミ箱の料理の完全削除に失敗しました", "error", err)
+		return
+	}
+	if count > 0 {
+		slog.InfoContext(ctx, "ゴミ箱の料理を完全削除しました", "count", count)
+	}
+}
+
+// purgeTarget 完全削除する料理の条件（$1 は保持期間の終了日時）
+// セットメニューの既定の料理として使われている料理は削除しない
+// （既定の料理はゴミ箱に移動できないため、移動できなくなる前にゴミ箱に入った料理のみが対象になる）
+const purgeTarget = `d.deleted_at < $1
+	AND NOT EXISTS (SELECT 1 FROM bundle_slots s WHERE s.default_dish_id = d.id)`
+
+// PurgeDeletedDishes 保持期間を過ぎたゴミ箱の料理を完全に削除し、削除件数を返す
+// セットメニューの既定の料理として使われている料理は削除しない
+// 料理の写真のファイルは、他の料理・セット・他の料理の変更履歴から参照されていなければ削除後にストレージからも削除する
+// （ストレージが設定されていない場合は料理のみ削除する）
+func PurgeDeletedDishes(ctx context.Context, retention time.Duration) (int64, error) {
+	var gcsClient *utils.GCSClient
+	if config.Get().GCS.BucketName != "" {
+		var err error
+		if gcsClient, err = utils.GetGCSClient(); err != nil {
+			return 0, err
+		}
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	cutoff := time.Now().Add(-retention)
+
+	// 削除する料理の写真（代表写真とギャラリーの写真）
+	rows, err := tx.Query(ctx,
+		`SELECT d.photo_url FROM dishes d WHERE `+purgeTarget+` AND d.photo_url <> ''
+		 UNION
+		 SELECT p.object_name FROM dish_photos p JOIN dishes d ON d.id = p.dish_id WHERE `+purgeTarget,
+		cutoff,
+	)
+	if err != nil {
+		return 0, err
+	}
+	photos, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+
+	// セットの差し替え候補からは外す
+	_, err = tx.Exec(ctx,
+		`DELETE FROM bundle_slot_choices c USING dishes d
+		 WHERE c.dish_id = d.id AND `+purgeTarget,
+		cutoff,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err = tx.Query(ctx, `DELETE FROM dishes d WHERE `+purgeTarget+` RETURNING d.id::text`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+
+	// 同じ内容の写真（ハッシュ名）を使う料理・セットや、他の料理の変更履歴の復元で使う写真は残す
+	// 削除した料理自身の変更履歴は、完全削除後に復元しても写真は戻らないものとして参照に含めない
+	rows, err = tx.Query(ctx,
+		`SELECT name FROM unnest($1::text[]) AS name
+		 WHERE NOT EXISTS (SELECT 1 FROM dish_photos WHERE object_name = name)
+		   AND NOT EXISTS (SELECT 1 FROM dishes WHERE photo_url = name)
+		   AND NOT EXISTS (SELECT 1 FROM bundles WHERE photo_url = name)
+		   AND NOT EXISTS (
+		       SELECT 1 FROM dish_revisions
+		       WHERE dish_id::text <> ALL($2) AND (old_values->>'img' = name OR new_values->>'img' = name))`,
+		photos, purged,
+	)
+	if err != nil {
+		return 0, err
+	}
+	unreferenced, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	if gcsClient == nil {
+		return int64(len(purged)), nil
+	}
+
+	// 料理の削除は確定しているため、ファイルの削除に失敗してもログに出力して続ける
+	for _, name := range unreferenced {
+		objectName := utils.ObjectName(name)
+		if err := gcsClient.DeleteFile(ctx, objectName); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			slog.ErrorContext(ctx, "完全削除した料理の写真ファイルの削除に失敗しました", "object", objectName, "error", err)
+		}
+	}
+
+	return int64(len(purged)), nil
+}
@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/smilemasa/go-api/db"
+	"github.com/smilemasa/go-api/utils"
+)
+
+// StartUploadSlotCleaner 使われずに期限切れになったアップロード枠と写真を定期的に削除するワーカーを起動する
+// ctx がキャンセルされると停止し、返り値のチャネルが閉じられる
+func StartUploadSlotCleaner(ctx context.Context, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purgeExpiredUploads(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return done
+}
+
+// purgeExpiredUploads 1回分の期限切れアップロード枠の削除を実行する
+func purgeExpiredUploads(ctx context.Context) {
+	count, err := PurgeExpiredUploads(ctx)
+	if count > 0 {
+		slog.InfoContext(ctx, "期限切れのアップロード枠を削除しました", "count", count)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "期限切れのアップロード枠の削除に失敗しました", "error", err)
+	}
+}
+
+// PurgeExpiredUploads 使われずに期限切れになったアップロード枠と、アップロードされた写真を削除し、削除件数を返す
+// 枠は写真の削除に成功してから1件ずつ削除する（失敗した枠は残り、次回に再試行する）
+func PurgeExpiredUploads(ctx context.Context) (int64, error) {
+	gcsClient, err := utils.GetGCSClient()
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := db.ConnectDB()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, `SELECT object_name FROM upload_slots WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	objectNames, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	var errs []error
+	for _, objectName := range objectNames {
+		// アップロードされなかった枠はファイルがないため、存在しない場合は削除済みとして扱う
+		if err := gcsClient.DeleteFile(ctx, objectName); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			errs = append(errs, fmt.Errorf("%s: %w", objectName, err))
+			continue
+		}
+		result, err := conn.Exec(ctx, `DELETE FROM upload_slots WHERE object_name = $1 AND expires_at <= now()`, objectName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", objectName, err))
+			continue
+		}
+		count += result.RowsAffected()
+	}
+
+	return count, errors.Join(errs...)
+}